@@ -10,10 +10,10 @@ func newAdd() *cobra.Command {
 		Short: "Add to an existing migration",
 		Args:  args(),
 	}
-	cmd.CompletionOptions.DisableDefaultCmd = true
 
 	cmd.AddCommand(newAddUpgrade())
 	cmd.AddCommand(newAddProto())
+	cmd.AddCommand(newAddLoad())
 
 	return cmd
 }
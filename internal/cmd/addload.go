@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/silas/jimmy/internal/migrations"
+)
+
+func newAddLoad() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "load [flags] file",
+		Short: "Add a CSV or newline-delimited JSON file to load into a table",
+		Args:  args("file"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ms, err := getMigrations(cmd, true)
+			if err != nil {
+				return err
+			}
+			defer ms.Close()
+
+			m, err := getMigration(cmd, ms)
+			if err != nil {
+				return err
+			}
+
+			table, err := cmd.Flags().GetString(flagTable)
+			if err != nil {
+				return err
+			}
+
+			envValue, err := cmd.Flags().GetString(flagEnv)
+			if err != nil {
+				return err
+			}
+
+			env, err := parseEnv(envValue)
+			if err != nil {
+				return err
+			}
+
+			err = ms.AddLoad(cmd.Context(), migrations.AddLoadInput{
+				ID:    m.ID(),
+				Path:  args[0],
+				Table: table,
+				Env:   env,
+			})
+			if err != nil {
+				return err
+			}
+
+			cmd.Println(m.Path())
+
+			return nil
+		},
+	}
+
+	setupMigrationFlag(cmd)
+
+	cmd.Flags().StringP(flagEnv, "e", "", "execution environment (GOOGLE_CLOUD, EMULATOR)")
+	cmd.Flags().StringP(flagTable, "", "", "table to load (default file name without extension)")
+
+	registerFlagCompletion(cmd, flagEnv, completeValues(builtinEnvs()...))
+
+	return cmd
+}
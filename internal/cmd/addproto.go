@@ -10,9 +10,9 @@ import (
 
 func newAddProto() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "proto [flags] name file",
-		Short: "Add a protobuf-serialized file descriptor set",
-		Args:  args("name", "file"),
+		Use:   "proto [flags] name file...",
+		Short: "Add a protobuf-serialized file descriptor set or .proto source files",
+		Args:  variadicArgs("name", "file"),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ms, err := getMigrations(cmd, true)
 			if err != nil {
@@ -31,10 +31,34 @@ func newAddProto() *cobra.Command {
 				return errors.New("path is required")
 			}
 
+			importPaths, err := cmd.Flags().GetStringArray(flagImportPath)
+			if err != nil {
+				return err
+			}
+
+			types, err := cmd.Flags().GetStringArray(flagIncludeType)
+			if err != nil {
+				return err
+			}
+
+			bundle, err := cmd.Flags().GetBool(flagBundle)
+			if err != nil {
+				return err
+			}
+
+			prune, err := cmd.Flags().GetBool(flagPruneBundle)
+			if err != nil {
+				return err
+			}
+
 			err = ms.AddProto(cmd.Context(), migrations.AddProtoInput{
-				ID:   m.ID(),
-				Name: args[0],
-				Path: args[1],
+				ID:          m.ID(),
+				Name:        args[0],
+				Paths:       args[1:],
+				ImportPaths: importPaths,
+				Types:       types,
+				Bundle:      bundle,
+				Prune:       prune,
 			})
 			if err != nil {
 				return err
@@ -48,5 +72,11 @@ func newAddProto() *cobra.Command {
 
 	setupMigrationFlag(cmd)
 
+	cmd.Flags().BoolP(flagBundle, "", false, "add a statement creating or altering the proto bundle")
+	cmd.Flags().StringArrayP(flagImportPath, "I", nil, "import path for .proto source files")
+	cmd.Flags().StringArrayP(flagIncludeType, "", nil,
+		"fully-qualified message or enum to keep (default types in proto bundle statements)")
+	cmd.Flags().BoolP(flagPruneBundle, "", false, "delete bundled types missing from the file descriptor set")
+
 	return cmd
 }
@@ -29,11 +29,15 @@ func newAddUpgrade() *cobra.Command {
 			}
 
 			err = ms.AddUpgrade(cmd.Context(), migrations.AddUpgradeInput{
-				ID:         m.ID(),
-				SQL:        flags.SQL,
-				TemplateID: flags.Template,
-				Env:        flags.Env,
-				Type:       flags.Type,
+				ID:          m.ID(),
+				SQL:         flags.SQL,
+				TemplateID:  flags.Template,
+				Env:         flags.Env,
+				Envs:        flags.Envs,
+				ExcludeEnvs: flags.ExcludeEnvs,
+				Type:        flags.Type,
+				When:        flags.When,
+				Batched:     flags.Batched,
 			})
 			if err != nil {
 				return err
@@ -12,6 +12,9 @@ const (
 	flagProject  = "project"
 	flagInstance = "instance"
 	flagDatabase = "database"
+	flagTarget   = "target"
+
+	flagCreateDatabase = "create-database"
 )
 
 func New() *cobra.Command {
@@ -23,19 +26,33 @@ func New() *cobra.Command {
 		SilenceUsage:  false,
 		SilenceErrors: true,
 	}
-	cmd.CompletionOptions.DisableDefaultCmd = true
 
 	cmd.PersistentFlags().StringP(flagConfig, "c", constants.ConfigFile, "configuration file")
 	cmd.PersistentFlags().BoolP(flagEmulator, "", false, "set whether to enable emulator mode (default automatically detected)")
 	cmd.PersistentFlags().StringP(flagProject, "p", "", "set Google project ID")
 	cmd.PersistentFlags().StringP(flagInstance, "i", "", "set Spanner instance ID")
 	cmd.PersistentFlags().StringP(flagDatabase, "d", "", "set Spanner database ID")
+	cmd.PersistentFlags().BoolP(flagCreateDatabase, "", false, "create the instance and database when they don't exist")
+	cmd.PersistentFlags().StringP(flagTarget, "", "", "set target from configuration")
+
+	registerFlagCompletion(cmd, flagTarget, completeTargets())
 
 	cmd.AddCommand(newInit())
 	cmd.AddCommand(newCreate())
 	cmd.AddCommand(newAdd())
 	cmd.AddCommand(newUpgrade())
+	cmd.AddCommand(newStatus())
+	cmd.AddCommand(newPlan())
+	cmd.AddCommand(newOperations())
+	cmd.AddCommand(newSeed())
 	cmd.AddCommand(newTemplates())
+	cmd.AddCommand(newSchema())
+	cmd.AddCommand(newDiff())
+	cmd.AddCommand(newExport())
+	cmd.AddCommand(newLint())
+	cmd.AddCommand(newValidate())
+	cmd.AddCommand(newRenumber())
+	cmd.AddCommand(newImport())
 
 	return cmd
 }
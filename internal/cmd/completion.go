@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/silas/jimmy/internal/migrations"
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
+)
+
+type completionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// completeMigrations returns a completion function for values read from
+// the loaded migrations.
+func completeMigrations(values func(ms *migrations.Migrations) []string) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		ms, err := setupMigrations(cmd, true)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		defer ms.Close()
+
+		return values(ms), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func completeMigrationIDs() completionFunc {
+	return completeMigrations(func(ms *migrations.Migrations) []string {
+		var ids []string
+
+		for m := range ms.All() {
+			ids = append(ids, fmt.Sprintf("%d\t%s", m.ID(), m.Name()))
+		}
+
+		return ids
+	})
+}
+
+func completeTemplates() completionFunc {
+	return completeMigrations(func(ms *migrations.Migrations) []string {
+		var templates []string
+
+		for templateID, template := range ms.Templates() {
+			value := templateID
+			if template.GetDefault() {
+				value += "\tdefault"
+			}
+
+			templates = append(templates, value)
+		}
+
+		slices.Sort(templates)
+
+		return templates
+	})
+}
+
+func completeTargets() completionFunc {
+	return completeMigrations(func(ms *migrations.Migrations) []string {
+		return slices.Sorted(maps.Keys(ms.Config.GetTargets()))
+	})
+}
+
+// completeEnvs returns a completion function for user-defined environments,
+// optionally including the built-in environments.
+func completeEnvs(builtin bool) completionFunc {
+	return completeMigrations(func(ms *migrations.Migrations) []string {
+		var envs []string
+
+		if builtin {
+			envs = builtinEnvs()
+		}
+
+		return append(envs, ms.Config.GetEnvironments()...)
+	})
+}
+
+func builtinEnvs() []string {
+	return enumNames(jimmyv1.Environment_name, jimmyv1.Environment_ALL.String())
+}
+
+func completeValues(values ...string) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// enumNames returns the names of an enum in value order, excluding the
+// skipped names.
+func enumNames(names map[int32]string, skip ...string) []string {
+	var values []string
+
+	for _, value := range slices.Sorted(maps.Keys(names)) {
+		if !slices.Contains(skip, names[value]) {
+			values = append(values, names[value])
+		}
+	}
+
+	return values
+}
+
+func registerFlagCompletion(cmd *cobra.Command, name string, f completionFunc) {
+	err := cmd.RegisterFlagCompletionFunc(name, f)
+	if err != nil {
+		panic(fmt.Sprintf("failed to register %s completion: %s", name, err))
+	}
+}
@@ -43,13 +43,23 @@ func newCreate() *cobra.Command {
 					return err
 				}
 
+				backupBefore, err := cmd.Flags().GetBool(flagBackupBefore)
+				if err != nil {
+					return err
+				}
+
 				m, err = ms.Create(cmd.Context(), migrations.CreateInput{
-					Name:       args[0],
-					SQL:        flags.SQL,
-					Env:        flags.Env,
-					TemplateID: flags.Template,
-					Type:       flags.Type,
-					SquashID:   squashID,
+					Name:         args[0],
+					SQL:          flags.SQL,
+					Env:          flags.Env,
+					Envs:         flags.Envs,
+					ExcludeEnvs:  flags.ExcludeEnvs,
+					TemplateID:   flags.Template,
+					Type:         flags.Type,
+					When:         flags.When,
+					Batched:      flags.Batched,
+					SquashID:     squashID,
+					BackupBefore: backupBefore,
 				})
 				if err != nil {
 					return err
@@ -66,6 +76,7 @@ func newCreate() *cobra.Command {
 
 	cmd.Flags().BoolP(flagBootstrap, "", false, "populate from current schema")
 	cmd.Flags().IntP(flagSquash, "", 0, "squash ID")
+	cmd.Flags().BoolP(flagBackupBefore, "", false, "back up the database before the migration runs")
 
 	return cmd
 }
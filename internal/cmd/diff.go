@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/silas/jimmy/internal/migrations"
+)
+
+func newDiff() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Diff the current schema against the desired schema file",
+		Args:  args(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ms, err := getMigrations(cmd, true)
+			if err != nil {
+				return err
+			}
+			defer ms.Close()
+
+			name, err := cmd.Flags().GetString(flagCreate)
+			if err != nil {
+				return err
+			}
+
+			path, err := cmd.Flags().GetString(flagFile)
+			if err != nil {
+				return err
+			}
+
+			replay, err := cmd.Flags().GetBool(flagReplay)
+			if err != nil {
+				return err
+			}
+
+			allowDestructive, err := cmd.Flags().GetBool(flagAllowDestructive)
+			if err != nil {
+				return err
+			}
+
+			if flagSet(cmd, flagCreate) {
+				m, err := ms.CreateDiff(cmd.Context(), migrations.CreateDiffInput{
+					Name:             name,
+					Path:             path,
+					Replay:           replay,
+					AllowDestructive: allowDestructive,
+				})
+				if err != nil {
+					return err
+				}
+
+				cmd.Println(m.Path())
+
+				return nil
+			}
+
+			statements, err := ms.Diff(cmd.Context(), migrations.DiffInput{
+				Path:             path,
+				Replay:           replay,
+				AllowDestructive: allowDestructive,
+			})
+			if err != nil {
+				return err
+			}
+
+			schema := &migrations.Schema{Statements: statements}
+
+			cmd.Print(schema.SQL())
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP(flagCreate, "", "", "create a migration with the given name")
+	cmd.Flags().StringP(flagFile, "f", "", "desired schema file (default from config)")
+	cmd.Flags().BoolP(flagReplay, "", false, "diff against a replay of the migrations in the emulator")
+	cmd.Flags().BoolP(flagAllowDestructive, "", false, "allow drop statements, such as dropping tables, columns and indexes")
+
+	return cmd
+}
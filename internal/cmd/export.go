@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/silas/jimmy/internal/migrations"
+)
+
+func newExport() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export migrations for review",
+		Args:  args(),
+	}
+
+	cmd.AddCommand(newExportSQL())
+
+	return cmd
+}
+
+func newExportSQL() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sql",
+		Short: "Export the statements an upgrade would run as a SQL script",
+		Args:  args(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ms, err := getMigrations(cmd, true)
+			if err != nil {
+				return err
+			}
+			defer ms.Close()
+
+			from, err := cmd.Flags().GetInt(flagFrom)
+			if err != nil {
+				return err
+			}
+
+			to, err := cmd.Flags().GetInt(flagTo)
+			if err != nil {
+				return err
+			}
+
+			env, err := parseBuiltinOrUserEnvFlag(cmd, ms)
+			if err != nil {
+				return err
+			}
+
+			output, err := cmd.Flags().GetString(flagOutput)
+			if err != nil {
+				return err
+			}
+
+			// buffer stdout so a failed export doesn't print a partial script
+			var buf bytes.Buffer
+
+			var w io.Writer = &buf
+			descriptorPath := ""
+
+			if output != "" {
+				err = os.MkdirAll(filepath.Dir(output), 0755)
+				if err != nil {
+					return err
+				}
+
+				f, err := os.Create(output)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+
+				w = f
+				descriptorPath = strings.TrimSuffix(output, ".sql") + "_"
+			}
+
+			files, err := ms.ExportSQL(w, migrations.ExportInput{
+				From:           from,
+				To:             to,
+				Env:            env,
+				DescriptorPath: descriptorPath,
+			})
+			if errors.Is(err, migrations.ErrNoDescriptorPath) {
+				return fmt.Errorf("--%s is required to export proto descriptors: %w", flagOutput, err)
+			} else if err != nil {
+				return err
+			}
+
+			_, err = buf.WriteTo(cmd.OutOrStdout())
+			if err != nil {
+				return err
+			}
+
+			for _, file := range files {
+				cmd.PrintErrln(fmt.Sprintf("Proto descriptors written to %s", file))
+			}
+
+			if output != "" {
+				cmd.PrintErrln(fmt.Sprintf("SQL written to %s", output))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().IntP(flagFrom, "", 0, "migration ID the database is at")
+	cmd.Flags().IntP(flagTo, "", 0, "last migration ID to export (default latest)")
+	cmd.Flags().StringP(flagEnv, "e", "", "execution environment (GOOGLE_CLOUD, EMULATOR or user-defined) (default automatically detected)")
+	cmd.Flags().StringP(flagOutput, "o", "", "file to write, required to export proto descriptors (default stdout)")
+
+	registerFlagCompletion(cmd, flagEnv, completeEnvs(true))
+
+	return cmd
+}
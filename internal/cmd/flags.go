@@ -5,23 +5,54 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/durationpb"
 
 	"github.com/silas/jimmy/internal/migrations"
 	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
 )
 
 const (
-	flagBootstrap = "bootstrap"
-	flagEnv       = "env"
-	flagMigration = "migration"
-	flagSQL       = "sql"
-	flagSquash    = "squash"
-	flagTemplate  = "template"
-	flagType      = "type"
+	flagAllowDestructive = "allow-destructive"
+	flagAllowOutOfOrder  = "allow-out-of-order"
+	flagBatchPause       = "batch-pause"
+	flagBatchSize        = "batch-size"
+	flagBackup           = "backup"
+	flagBackupBefore     = "backup-before"
+	flagBootstrap        = "bootstrap"
+	flagBundle           = "bundle"
+	flagCreate           = "create"
+	flagDefault          = "default"
+	flagDryRun           = "dry-run"
+	flagDumpSchema       = "dump-schema"
+	flagEnv              = "env"
+	flagEnvs             = "envs"
+	flagExcludeEnvs      = "exclude-envs"
+	flagFile             = "file"
+	flagFrom             = "from"
+	flagImportPath       = "import-path"
+	flagIncludeType      = "include-type"
+	flagMarkApplied      = "mark-applied"
+	flagMaxIterations    = "max-iterations"
+	flagMigration        = "migration"
+	flagNoWait           = "no-wait"
+	flagOutput           = "output"
+	flagPruneBundle      = "prune-bundle"
+	flagReplay           = "replay"
+	flagSQL              = "sql"
+	flagSQLFile          = "sql-file"
+	flagSquash           = "squash"
+	flagTable            = "table"
+	flagTemplate         = "template"
+	flagTo               = "to"
+	flagType             = "type"
+	flagVersionTable     = "version-table"
+	flagWhen             = "when"
 )
 
 func setupMigrationFlag(cmd *cobra.Command) {
 	cmd.Flags().IntP(flagMigration, "m", 0, "migration ID")
+
+	registerFlagCompletion(cmd, flagMigration, completeMigrationIDs())
 }
 
 func parseMigrationFlag(cmd *cobra.Command) (int, error) {
@@ -31,15 +62,35 @@ func parseMigrationFlag(cmd *cobra.Command) (int, error) {
 func setupStatementFlags(cmd *cobra.Command) {
 	cmd.Flags().StringP(flagSQL, "s", "", "migration SQL")
 	cmd.Flags().StringP(flagEnv, "e", "", "execution environment (GOOGLE_CLOUD, EMULATOR)")
+	cmd.Flags().StringSliceP(flagEnvs, "", nil, "user-defined environments in which to run the statement")
+	cmd.Flags().StringSliceP(flagExcludeEnvs, "", nil, "user-defined environments in which to skip the statement")
 	cmd.Flags().StringP(flagTemplate, "t", "", "SQL template")
-	cmd.Flags().StringP(flagType, "", "", "type of statement (DDL, DML, PARTITIONED_DML)")
+	cmd.Flags().StringP(flagWhen, "", "", "read-only SQL query that must return true for the statement to run")
+	cmd.Flags().StringP(flagType, "", "", "type of statement (DDL, DML, PARTITIONED_DML, BATCHED_DML, ASSERT)")
+	cmd.Flags().Int64P(flagBatchSize, "", 0, "batched DML batch size (default 1000)")
+	cmd.Flags().DurationP(flagBatchPause, "", 0, "batched DML pause between batches")
+	cmd.Flags().Int64P(flagMaxIterations, "", 0, "batched DML maximum number of batches")
+
+	registerFlagCompletion(cmd, flagEnv, completeValues(builtinEnvs()...))
+	registerFlagCompletion(cmd, flagEnvs, completeEnvs(false))
+	registerFlagCompletion(cmd, flagExcludeEnvs, completeEnvs(false))
+	registerFlagCompletion(cmd, flagTemplate, completeTemplates())
+	registerFlagCompletion(cmd, flagType, completeValues(enumNames(
+		jimmyv1.Type_name,
+		jimmyv1.Type_AUTOMATIC.String(),
+		jimmyv1.Type_LOAD.String(),
+	)...))
 }
 
 type statementFlags struct {
-	SQL      string
-	Template string
-	Env      jimmyv1.Environment
-	Type     jimmyv1.Type
+	SQL         string
+	Template    string
+	Env         jimmyv1.Environment
+	Envs        []string
+	ExcludeEnvs []string
+	Type        jimmyv1.Type
+	When        string
+	Batched     *jimmyv1.BatchedOptions
 }
 
 func parseStatementFlags(cmd *cobra.Command) (flags statementFlags, err error) {
@@ -58,15 +109,24 @@ func parseStatementFlags(cmd *cobra.Command) (flags statementFlags, err error) {
 		return flags, err
 	}
 
-	if envValue != "" {
-		envValue = strings.ToUpper(migrations.Slugify(envValue))
+	flags.Env, err = parseEnv(envValue)
+	if err != nil {
+		return flags, err
+	}
 
-		envInt, found := jimmyv1.Environment_value[envValue]
-		if !found {
-			return flags, fmt.Errorf("%q is not a valid env", envValue)
-		}
+	flags.Envs, err = cmd.Flags().GetStringSlice(flagEnvs)
+	if err != nil {
+		return flags, err
+	}
+
+	flags.ExcludeEnvs, err = cmd.Flags().GetStringSlice(flagExcludeEnvs)
+	if err != nil {
+		return flags, err
+	}
 
-		flags.Env = jimmyv1.Environment(envInt)
+	flags.When, err = cmd.Flags().GetString(flagWhen)
+	if err != nil {
+		return flags, err
 	}
 
 	typeValue, err := cmd.Flags().GetString(flagType)
@@ -77,11 +137,91 @@ func parseStatementFlags(cmd *cobra.Command) (flags statementFlags, err error) {
 	if typeValue != "" {
 		typeInt, found := jimmyv1.Type_value[typeValue]
 		if !found {
-			return flags, fmt.Errorf("%q is not a valid type", envValue)
+			return flags, fmt.Errorf("%q is not a valid type", typeValue)
 		}
 
 		flags.Type = jimmyv1.Type(typeInt)
 	}
 
+	if flagSet(cmd, flagBatchSize) || flagSet(cmd, flagBatchPause) || flagSet(cmd, flagMaxIterations) {
+		flags.Batched = &jimmyv1.BatchedOptions{}
+
+		flags.Batched.Size, err = cmd.Flags().GetInt64(flagBatchSize)
+		if err != nil {
+			return flags, err
+		}
+
+		pause, err := cmd.Flags().GetDuration(flagBatchPause)
+		if err != nil {
+			return flags, err
+		}
+
+		if pause > 0 {
+			flags.Batched.Pause = durationpb.New(pause)
+		}
+
+		flags.Batched.MaxIterations, err = cmd.Flags().GetInt64(flagMaxIterations)
+		if err != nil {
+			return flags, err
+		}
+	}
+
 	return flags, nil
 }
+
+func setupEnvFlag(cmd *cobra.Command) {
+	cmd.Flags().StringP(flagEnv, "e", "", "user-defined environment (default from target)")
+
+	registerFlagCompletion(cmd, flagEnv, completeEnvs(false))
+}
+
+// parseEnvFlag sets the active user-defined environment from the env flag.
+func parseEnvFlag(cmd *cobra.Command, ms *migrations.Migrations) error {
+	env, err := cmd.Flags().GetString(flagEnv)
+	if err != nil {
+		return err
+	}
+
+	if env == "" {
+		return nil
+	}
+
+	return ms.SetEnv(env)
+}
+
+// parseBuiltinOrUserEnvFlag parses the env flag of commands accepting both
+// kinds of environments, returning the built-in environment or activating
+// the user-defined one.
+func parseBuiltinOrUserEnvFlag(cmd *cobra.Command, ms *migrations.Migrations) (jimmyv1.Environment, error) {
+	value, err := cmd.Flags().GetString(flagEnv)
+	if err != nil {
+		return jimmyv1.Environment_ALL, err
+	}
+
+	env, err := parseEnv(value)
+	if err == nil {
+		return env, nil
+	}
+
+	err = ms.SetEnv(value)
+	if err != nil {
+		return jimmyv1.Environment_ALL, fmt.Errorf("invalid env: %w", err)
+	}
+
+	return jimmyv1.Environment_ALL, nil
+}
+
+func parseEnv(value string) (jimmyv1.Environment, error) {
+	if value == "" {
+		return jimmyv1.Environment_ALL, nil
+	}
+
+	value = strings.ToUpper(migrations.Slugify(value))
+
+	env, found := jimmyv1.Environment_value[value]
+	if !found {
+		return jimmyv1.Environment_ALL, fmt.Errorf("%q is not a valid env", value)
+	}
+
+	return jimmyv1.Environment(env), nil
+}
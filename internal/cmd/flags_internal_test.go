@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
+)
+
+func TestParseStatementFlags(t *testing.T) {
+	cmd := &cobra.Command{}
+	setupStatementFlags(cmd)
+
+	require.NoError(t, cmd.Flags().Set(flagEnv, "EMULATOR"))
+	require.NoError(t, cmd.Flags().Set(flagType, "DML"))
+
+	flags, err := parseStatementFlags(cmd)
+	require.NoError(t, err)
+	require.Equal(t, jimmyv1.Environment_EMULATOR, flags.Env)
+	require.Equal(t, jimmyv1.Type_DML, flags.Type)
+
+	require.NoError(t, cmd.Flags().Set(flagType, "QUERY"))
+
+	_, err = parseStatementFlags(cmd)
+	require.EqualError(t, err, `"QUERY" is not a valid type`)
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/silas/jimmy/internal/constants"
+	"github.com/silas/jimmy/internal/migrations"
+)
+
+func newImport() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import [flags] dir",
+		Short: "Import migrations from another tool",
+		Args:  args("dir"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ms, err := getMigrations(cmd, true)
+			if err != nil {
+				return err
+			}
+			defer ms.Close()
+
+			from, err := cmd.Flags().GetString(flagFrom)
+			if err != nil {
+				return err
+			}
+			if from == "" {
+				return fmt.Errorf("--%s is required", flagFrom)
+			}
+
+			markApplied, err := cmd.Flags().GetBool(flagMarkApplied)
+			if err != nil {
+				return err
+			}
+
+			versionTable, err := cmd.Flags().GetString(flagVersionTable)
+			if err != nil {
+				return err
+			}
+
+			imported, err := ms.Import(cmd.Context(), migrations.ImportInput{
+				From:         from,
+				Path:         args[0],
+				MarkApplied:  markApplied,
+				VersionTable: versionTable,
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, im := range imported {
+				if im.Migration == nil {
+					cmd.Println(fmt.Sprintf("%s skipped, no statements", im.FileName))
+					continue
+				}
+
+				suffix := ""
+				if im.Applied {
+					suffix = " (applied)"
+				}
+
+				cmd.Println(fmt.Sprintf("%s -> %s%s", im.FileName, im.Migration.FileName(), suffix))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP(flagFrom, "", "", fmt.Sprintf(
+		"tool the migrations were written for (%s, %s; %s isn't supported)",
+		constants.ImportGolangMigrate,
+		constants.ImportWrench,
+		constants.ImportLiquibase,
+	))
+	cmd.Flags().BoolP(flagMarkApplied, "", false, "mark migrations in the tool's version table as applied")
+	cmd.Flags().StringP(flagVersionTable, "", constants.ImportVersionTable, "version table of the tool")
+
+	registerFlagCompletion(cmd, flagFrom, completeValues(constants.ImportGolangMigrate, constants.ImportWrench))
+
+	return cmd
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func newLint() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Check migrations for problems",
+		Args:  args(),
+	}
+
+	cmd.AddCommand(newLintProtos())
+
+	return cmd
+}
+
+func newLintProtos() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "protos",
+		Short: "Check proto bundle changes are compatible with existing data",
+		Args:  args(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ms, err := getMigrations(cmd, true)
+			if err != nil {
+				return err
+			}
+			defer ms.Close()
+
+			problems, err := ms.LintProtos()
+			if err != nil {
+				return err
+			}
+
+			for _, problem := range problems {
+				cmd.Println(problem.String())
+			}
+
+			if len(problems) > 0 {
+				return fmt.Errorf("found %d breaking proto changes", len(problems))
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -10,9 +10,28 @@ import (
 
 	"github.com/silas/jimmy/internal/constants"
 	"github.com/silas/jimmy/internal/migrations"
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
 )
 
 func getMigrations(cmd *cobra.Command, load bool) (*migrations.Migrations, error) {
+	m, err := setupMigrations(cmd, load)
+	if err != nil {
+		return nil, err
+	}
+
+	if load {
+		err = m.Validate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// setupMigrations returns migrations configured from the flags, without
+// validating the loaded migration files.
+func setupMigrations(cmd *cobra.Command, load bool) (*migrations.Migrations, error) {
 	configPath, err := cmd.Flags().GetString(flagConfig)
 	if err != nil {
 		return nil, err
@@ -21,7 +40,7 @@ func getMigrations(cmd *cobra.Command, load bool) (*migrations.Migrations, error
 	m := migrations.New(configPath)
 
 	if load {
-		err = m.Load(cmd.Context())
+		err = m.LoadAll(cmd.Context())
 		if err != nil {
 			if errors.Is(err, fs.ErrNotExist) {
 				if flagSet(cmd, flagConfig) {
@@ -33,6 +52,17 @@ func getMigrations(cmd *cobra.Command, load bool) (*migrations.Migrations, error
 		}
 	}
 
+	target, err := cmd.Flags().GetString(flagTarget)
+	if err != nil {
+		return nil, err
+	}
+	if target != "" {
+		err = m.SetTarget(target)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	project, err := cmd.Flags().GetString(flagProject)
 	if err != nil {
 		return nil, err
@@ -69,6 +99,17 @@ func getMigrations(cmd *cobra.Command, load bool) (*migrations.Migrations, error
 		m.Config.DatabaseId = os.Getenv(constants.EnvDatabaseID)
 	}
 
+	createDatabase, err := cmd.Flags().GetBool(flagCreateDatabase)
+	if err != nil {
+		return nil, err
+	}
+	if createDatabase {
+		if m.Config.CreateDatabase == nil {
+			m.Config.CreateDatabase = &jimmyv1.CreateDatabase{}
+		}
+		m.Config.CreateDatabase.Enabled = true
+	}
+
 	if flagSet(cmd, flagEmulator) {
 		emulator, err := cmd.Flags().GetBool(flagEmulator)
 		if err != nil {
@@ -86,13 +127,6 @@ func getMigrations(cmd *cobra.Command, load bool) (*migrations.Migrations, error
 		}
 	}
 
-	if load {
-		err = m.Validate()
-		if err != nil {
-			return nil, err
-		}
-	}
-
 	return m, nil
 }
 
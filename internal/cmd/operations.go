@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/silas/jimmy/internal/migrations"
+)
+
+func newOperations() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "operations",
+		Short: "Manage in-flight schema changes",
+		Args:  args(),
+	}
+
+	cmd.AddCommand(newOperationsList())
+	cmd.AddCommand(newOperationsStatus())
+	cmd.AddCommand(newOperationsWait())
+
+	return cmd
+}
+
+func newOperationsList() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the schema changes migrations are waiting for",
+		Args:  args(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ms, err := getMigrations(cmd, true)
+			if err != nil {
+				return err
+			}
+			defer ms.Close()
+
+			operations, err := ms.Operations(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			if len(operations) == 0 {
+				cmd.Println("No pending operations")
+				return nil
+			}
+
+			for _, op := range operations {
+				cmd.Println(fmt.Sprintf("migration[%d]: %s (%s)", op.MigrationID, op.Name, operationState(op)))
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func newOperationsStatus() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the progress of the schema changes migrations are waiting for",
+		Args:  args(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ms, err := getMigrations(cmd, true)
+			if err != nil {
+				return err
+			}
+			defer ms.Close()
+
+			operations, err := ms.Operations(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			if len(operations) == 0 {
+				cmd.Println("No pending operations")
+				return nil
+			}
+
+			for _, op := range operations {
+				cmd.Println(fmt.Sprintf("migration[%d]: %s (%s)", op.MigrationID, op.Name, operationState(op)))
+
+				if op.Err != nil {
+					cmd.Println(fmt.Sprintf("  Error: %s", op.Err))
+				}
+
+				metadata := op.Metadata
+				if metadata == nil {
+					continue
+				}
+
+				for i, sql := range metadata.Statements {
+					var progress int32
+
+					if i < len(metadata.Progress) {
+						progress = metadata.Progress[i].GetProgressPercent()
+					}
+
+					if i < len(metadata.CommitTimestamps) {
+						progress = 100
+					}
+
+					cmd.Println(fmt.Sprintf("  %3d%% %s", progress, firstLine(sql)))
+				}
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func newOperationsWait() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wait",
+		Short: "Wait for schema changes and complete their migrations",
+		Args:  args(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ms, err := getMigrations(cmd, true)
+			if err != nil {
+				return err
+			}
+			defer ms.Close()
+
+			var startTime time.Time
+
+			operations, err := ms.WaitOperations(
+				cmd.Context(),
+				func(op *migrations.Operation) {
+					startTime = time.Now()
+
+					cmd.Println(fmt.Sprintf("migration[%d]: Waiting for %s", op.MigrationID, op.Name))
+				},
+				func(op *migrations.Operation) {
+					cmd.Println(fmt.Sprintf("migration[%d]: Completed %s", op.MigrationID, displayDuration(startTime)))
+				},
+				upgradeOutputOptions(cmd)...,
+			)
+			if err != nil {
+				return err
+			}
+
+			if len(operations) == 0 {
+				cmd.Println("No pending operations")
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func operationState(op *migrations.Operation) string {
+	switch {
+	case op.Err != nil:
+		return "failed"
+	case op.Done:
+		return "done"
+	default:
+		return "running"
+	}
+}
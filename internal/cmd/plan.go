@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func newPlan() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Show the statements an upgrade would run",
+		Args:  args(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ms, err := getMigrations(cmd, true)
+			if err != nil {
+				return err
+			}
+			defer ms.Close()
+
+			err = parseEnvFlag(cmd, ms)
+			if err != nil {
+				return err
+			}
+
+			plan, err := ms.Plan(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			cmd.Println(fmt.Sprintf("Current migration: %d", plan.Status.CurrentID))
+
+			if len(plan.Migrations) == 0 {
+				cmd.Println("No pending migrations")
+				return nil
+			}
+
+			for _, pm := range plan.Migrations {
+				cmd.Println(fmt.Sprintf("migration[%d]: %s", pm.Migration.ID(), pm.Migration.Name()))
+
+				for pos, ps := range pm.Statements {
+					var suffix string
+
+					switch {
+					case !ps.Enabled:
+						suffix = " (skipped in environment)"
+					case ps.WhenErr != nil:
+						suffix = fmt.Sprintf(" (when failed: %s)", ps.WhenErr)
+					case ps.When != nil && *ps.When:
+						suffix = " (when true)"
+					case ps.When != nil:
+						suffix = " (when false, skipped)"
+					}
+
+					sql := firstLine(ps.Statement.Sql)
+
+					if load := ps.Statement.GetLoad(); load != nil {
+						sql = fmt.Sprintf("%s into %s", load.GetFile(), load.GetTable())
+					}
+
+					cmd.Println(fmt.Sprintf(
+						"  upgrade[%d]: %s %s%s",
+						pos,
+						ps.Statement.Type.String(),
+						sql,
+						suffix,
+					))
+				}
+			}
+
+			return nil
+		},
+	}
+
+	setupEnvFlag(cmd)
+
+	return cmd
+}
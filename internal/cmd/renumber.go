@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/silas/jimmy/internal/migrations"
+)
+
+func newRenumber() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "renumber",
+		Short: "Move conflicting migrations to the next free IDs",
+		Args:  args(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ms, err := setupMigrations(cmd, true)
+			if err != nil {
+				return err
+			}
+			defer ms.Close()
+
+			dryRun, err := cmd.Flags().GetBool(flagDryRun)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+			applied, err := ms.AppliedIDs(ctx)
+			cancel()
+			if err != nil {
+				cmd.PrintErrln(fmt.Sprintf("Migration table not reachable, ignoring applied migrations: %s", err))
+			}
+
+			result, err := ms.Renumber(migrations.RenumberInput{
+				Applied: applied,
+				DryRun:  dryRun,
+			})
+			if err != nil {
+				return err
+			}
+
+			if len(result.Unapplied) > 0 {
+				cmd.Println(fmt.Sprintf("Unapplied migrations: %s", joinIDs(result.Unapplied)))
+			}
+
+			if len(result.Conflicts) == 0 {
+				cmd.Println("No conflicting migrations")
+				return nil
+			}
+
+			cmd.Println(fmt.Sprintf("Conflicting migrations: %s", joinIDs(result.Conflicts)))
+
+			for _, r := range result.Renumbered {
+				cmd.Println(fmt.Sprintf("%s -> %s", r.FromFileName, r.FileName))
+
+				if r.SquashID != nil {
+					cmd.Println(fmt.Sprintf("  squash_id: %d", *r.SquashID))
+				}
+
+				if r.Applied {
+					cmd.PrintErrln(fmt.Sprintf(
+						"Migration %d has been applied, check the migration that kept the ID is the applied one",
+						r.FromID,
+					))
+				}
+			}
+
+			for _, r := range result.Updated {
+				cmd.Println(r.FileName)
+				cmd.Println(fmt.Sprintf("  squash_id: %d", *r.SquashID))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolP(flagDryRun, "", false, "show the changes without renaming any files")
+
+	return cmd
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newSchema() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Manage the schema dump",
+		Args:  args(),
+	}
+
+	cmd.AddCommand(newSchemaDump())
+	cmd.AddCommand(newSchemaCheck())
+
+	return cmd
+}
+
+func newSchemaDump() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dump",
+		Short: "Write the current schema to the schema file",
+		Args:  args(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ms, err := getMigrations(cmd, true)
+			if err != nil {
+				return err
+			}
+			defer ms.Close()
+
+			err = ms.DumpSchema(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			cmd.Println(ms.SchemaPath())
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func newSchemaCheck() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check the schema file matches a replay of the migrations",
+		Args:  args(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ms, err := getMigrations(cmd, true)
+			if err != nil {
+				return err
+			}
+			defer ms.Close()
+
+			return ms.CheckSchema(cmd.Context())
+		},
+	}
+
+	return cmd
+}
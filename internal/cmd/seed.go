@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/silas/jimmy/internal/migrations"
+)
+
+func newSeed() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Write seed data files",
+		Args:  args(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ms, err := getMigrations(cmd, true)
+			if err != nil {
+				return err
+			}
+			defer ms.Close()
+
+			env, err := parseBuiltinOrUserEnvFlag(cmd, ms)
+			if err != nil {
+				return err
+			}
+
+			seeds, err := ms.Seed(cmd.Context(), migrations.SeedInput{
+				Env: env,
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, seed := range seeds {
+				if seed.Skipped {
+					cmd.Println(fmt.Sprintf("seed[%s]: Unchanged", seed.Path))
+					continue
+				}
+
+				cmd.Println(fmt.Sprintf("seed[%s]: Wrote %d rows to %s", seed.Path, seed.Rows, seed.Table))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP(flagEnv, "e", "", "seed environment (GOOGLE_CLOUD, EMULATOR or user-defined) (default automatically detected)")
+
+	registerFlagCompletion(cmd, flagEnv, completeEnvs(true))
+
+	return cmd
+}
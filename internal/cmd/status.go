@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/spf13/cobra"
+)
+
+func newStatus() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show applied and pending migrations",
+		Args:  args(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ms, err := getMigrations(cmd, true)
+			if err != nil {
+				return err
+			}
+			defer ms.Close()
+
+			status, err := ms.Status(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			cmd.Println(fmt.Sprintf("Current migration: %d", status.CurrentID))
+
+			for _, id := range status.Incomplete {
+				cmd.Println(fmt.Sprintf("Incomplete migration: %d", id))
+			}
+
+			for _, option := range status.DatabaseOptions {
+				cmd.Println(fmt.Sprintf("Database option drift: %s", option))
+			}
+
+			if len(status.Pending) == 0 {
+				cmd.Println("No pending migrations")
+				return nil
+			}
+
+			cmd.Println("Pending migrations:")
+
+			for _, m := range status.Pending {
+				var suffix string
+
+				if slices.Contains(status.OutOfOrder, m) {
+					suffix = " (out of order)"
+				}
+
+				cmd.Println(fmt.Sprintf("  %d %s%s", m.ID(), m.Name(), suffix))
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
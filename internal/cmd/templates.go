@@ -1,7 +1,11 @@
 package cmd
 
 import (
-	"iter"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -12,26 +16,251 @@ import (
 func newTemplates() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "templates",
-		Short: "Show templates",
+		Short: "Manage templates",
 		Args:  args(),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			var templates iter.Seq2[string, *jimmyv1.Template]
+			ms, err := getTemplateMigrations(cmd)
+			if err != nil {
+				return err
+			}
+			defer ms.Close()
+
+			for templateID, template := range ms.Templates() {
+				_, source, err := ms.Template(templateID)
+				if err != nil {
+					return err
+				}
+
+				if template.GetDefault() {
+					source += ", default"
+				}
+
+				cmd.Println(fmt.Sprintf("%s (%s)", templateID, source))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.AddCommand(newTemplatesShow())
+	cmd.AddCommand(newTemplatesAdd())
+	cmd.AddCommand(newTemplatesRemove())
+	cmd.AddCommand(newTemplatesSetDefault())
+
+	return cmd
+}
 
+func newTemplatesShow() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "show ID",
+		Short:             "Show a template",
+		Args:              args("ID"),
+		ValidArgsFunction: completeTemplateArg(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ms, err := getTemplateMigrations(cmd)
+			if err != nil {
+				return err
+			}
+			defer ms.Close()
+
+			template, source, err := ms.Template(args[0])
+			if err != nil {
+				return err
+			}
+
+			cmd.Println(fmt.Sprintf("ID: %s", args[0]))
+			cmd.Println(fmt.Sprintf("Source: %s", source))
+			cmd.Println(fmt.Sprintf("Default: %t", template.GetDefault()))
+			cmd.Println(fmt.Sprintf("Type: %s", template.GetType()))
+			cmd.Println(fmt.Sprintf("Env: %s", template.GetEnv()))
+			cmd.Println("SQL:")
+			cmd.Println(strings.TrimSpace(template.GetSql()))
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func newTemplatesAdd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add ID",
+		Short: "Add a template to the configuration",
+		Args:  args("ID"),
+		RunE: func(cmd *cobra.Command, args []string) error {
 			ms, err := getMigrations(cmd, true)
 			if err != nil {
-				templates = migrations.BuiltinTemplates()
-			} else {
-				templates = ms.Templates()
-				ms.Close()
+				return err
+			}
+			defer ms.Close()
+
+			input := migrations.AddTemplateInput{ID: args[0]}
+
+			input.SQL, err = cmd.Flags().GetString(flagSQL)
+			if err != nil {
+				return err
+			}
+
+			sqlFile, err := cmd.Flags().GetString(flagSQLFile)
+			if err != nil {
+				return err
 			}
 
-			for templateID := range templates {
-				cmd.Println(templateID)
+			if sqlFile != "" {
+				if input.SQL != "" {
+					return fmt.Errorf("--%s and --%s can't both be set", flagSQL, flagSQLFile)
+				}
+
+				b, err := os.ReadFile(sqlFile)
+				if err != nil {
+					return err
+				}
+
+				input.SQL = string(b)
+			}
+
+			if strings.TrimSpace(input.SQL) == "" {
+				return fmt.Errorf("--%s or --%s required", flagSQLFile, flagSQL)
+			}
+
+			envValue, err := cmd.Flags().GetString(flagEnv)
+			if err != nil {
+				return err
+			}
+
+			input.Env, err = parseEnv(envValue)
+			if err != nil {
+				return err
+			}
+
+			typeValue, err := cmd.Flags().GetString(flagType)
+			if err != nil {
+				return err
+			}
+
+			if typeValue != "" {
+				typeInt, found := jimmyv1.Type_value[typeValue]
+				if !found {
+					return fmt.Errorf("%q is not a valid type", typeValue)
+				}
+
+				input.Type = jimmyv1.Type(typeInt)
 			}
 
+			input.Default, err = cmd.Flags().GetBool(flagDefault)
+			if err != nil {
+				return err
+			}
+
+			err = ms.AddTemplate(input)
+			if err != nil {
+				return err
+			}
+
+			cmd.Println(fmt.Sprintf("Added %q template to %s", input.ID, ms.Path))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP(flagSQLFile, "f", "", "file containing the template SQL")
+	cmd.Flags().StringP(flagSQL, "s", "", "template SQL")
+	cmd.Flags().StringP(flagEnv, "e", "", "execution environment (GOOGLE_CLOUD, EMULATOR)")
+	cmd.Flags().StringP(flagType, "", "", "type of statement (DDL, DML, PARTITIONED_DML, BATCHED_DML, ASSERT)")
+	cmd.Flags().BoolP(flagDefault, "", false, "mark as the default template")
+
+	registerFlagCompletion(cmd, flagEnv, completeValues(builtinEnvs()...))
+	registerFlagCompletion(cmd, flagType, completeValues(enumNames(
+		jimmyv1.Type_name,
+		jimmyv1.Type_AUTOMATIC.String(),
+		jimmyv1.Type_LOAD.String(),
+	)...))
+
+	return cmd
+}
+
+func newTemplatesRemove() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "remove ID",
+		Short:             "Remove a template from the configuration",
+		Args:              args("ID"),
+		ValidArgsFunction: completeTemplateArg(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ms, err := getMigrations(cmd, true)
+			if err != nil {
+				return err
+			}
+			defer ms.Close()
+
+			err = ms.RemoveTemplate(args[0])
+			if err != nil {
+				return err
+			}
+
+			cmd.Println(fmt.Sprintf("Removed %q template from %s", args[0], ms.Path))
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func newTemplatesSetDefault() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "set-default ID",
+		Short:             "Mark a template as the default",
+		Args:              args("ID"),
+		ValidArgsFunction: completeTemplateArg(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ms, err := getMigrations(cmd, true)
+			if err != nil {
+				return err
+			}
+			defer ms.Close()
+
+			err = ms.SetDefaultTemplate(args[0])
+			if err != nil {
+				return err
+			}
+
+			cmd.Println(fmt.Sprintf("Marked %q as the default template in %s", args[0], ms.Path))
+
 			return nil
 		},
 	}
 
 	return cmd
 }
+
+// getTemplateMigrations returns the configured migrations, falling back to
+// the builtin templates when there's no configuration.
+func getTemplateMigrations(cmd *cobra.Command) (*migrations.Migrations, error) {
+	configPath, err := cmd.Flags().GetString(flagConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = os.Stat(configPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return migrations.New(""), nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return getMigrations(cmd, true)
+}
+
+// completeTemplateArg completes the template ID argument.
+func completeTemplateArg() completionFunc {
+	templates := completeTemplates()
+
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		return templates(cmd, args, toComplete)
+	}
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplates_config(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), ".jimmy.yaml")
+
+	run := func() (string, error) {
+		var b strings.Builder
+
+		cmd := New()
+		cmd.SetOut(&b)
+		cmd.SetErr(&b)
+		cmd.SetArgs([]string{"templates", "--config", configPath})
+
+		err := cmd.Execute()
+		return b.String(), err
+	}
+
+	// builtin templates are listed without a configuration
+	out, err := run()
+	require.NoError(t, err)
+	require.Contains(t, out, "create-table (builtin, default)")
+
+	require.NoError(t, os.WriteFile(configPath, []byte("project_id: [\n"), 0644))
+
+	_, err = run()
+	require.ErrorContains(t, err, "yaml")
+}
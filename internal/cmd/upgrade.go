@@ -1,12 +1,14 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/silas/jimmy/internal/migrations"
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
 )
 
 func newUpgrade() *cobra.Command {
@@ -22,36 +24,69 @@ func newUpgrade() *cobra.Command {
 			}
 			defer ms.Close()
 
+			err = parseEnvFlag(cmd, ms)
+			if err != nil {
+				return err
+			}
+
+			allowOutOfOrder, err := cmd.Flags().GetBool(flagAllowOutOfOrder)
+			if err != nil {
+				return err
+			}
+
+			noWait, err := cmd.Flags().GetBool(flagNoWait)
+			if err != nil {
+				return err
+			}
+
+			backup, err := cmd.Flags().GetBool(flagBackup)
+			if err != nil {
+				return err
+			}
+
+			var operation string
+
 			var migrationStartTime time.Time
 
 			upgradeStartTime := time.Now()
 
-			err = ms.Upgrade(
-				cmd.Context(),
-				migrations.UpgradeOnStart(func(m *migrations.Migration) {
-					migrationStartTime = time.Now()
+			opts := append(
+				upgradeOutputOptions(cmd),
+				migrations.UpgradeAllowOutOfOrder(allowOutOfOrder),
+				migrations.UpgradeNoWait(noWait),
+				migrations.UpgradeBackup(backup),
+				migrations.UpgradeOnBackup(func(m *migrations.Migration, backup *migrations.Backup) {
+					if backup.Name != "" {
+						cmd.Println(fmt.Sprintf("migration[%d]: Created backup %s", m.ID(), backup.Name))
+					} else {
+						cmd.Println(fmt.Sprintf(
+							"migration[%d]: Recorded restore time %s",
+							m.ID(),
+							backup.VersionTime.UTC().Format(time.RFC3339Nano),
+						))
+					}
 
-					cmd.Println(fmt.Sprintf("migration[%d]: Started %q", m.ID(), m.Name()))
+					cmd.Println(fmt.Sprintf("migration[%d]: Restore with: %s", m.ID(), ms.RestoreCommand(backup)))
 				}),
-				migrations.UpgradeOnBatch(func(m *migrations.Migration, batch *migrations.Batch) {
-					var suffix string
+				migrations.UpgradeOnOperation(func(m *migrations.Migration, name string) {
+					operation = name
 
-					if len(batch.Statements) != 1 {
-						suffix = "s"
+					cmd.Println(fmt.Sprintf("migration[%d]: Submitted %s", m.ID(), name))
+				}),
+				migrations.UpgradeOnDatabaseOptions(func(options []*migrations.DatabaseOption) {
+					for _, option := range options {
+						cmd.Println(fmt.Sprintf("Set database option %s", option))
 					}
+				}),
+				migrations.UpgradeOnReattach(func(m *migrations.Migration, name string) {
+					migrationStartTime = time.Now()
 
-					if batch.FileDescriptorSet != "" {
-						suffix += fmt.Sprintf(" with file descriptor set %q",
-							batch.FileDescriptorSet)
-					}
+					cmd.Println(fmt.Sprintf("migration[%d]: Waiting for interrupted %s", m.ID(), name))
+				}),
+				migrations.UpgradeOnStart(func(m *migrations.Migration) {
+					migrationStartTime = time.Now()
 
-					cmd.Println(fmt.Sprintf(
-						"migration[%d]: Running %d %s statement%s",
-						m.ID(),
-						len(batch.Statements),
-						batch.Statements[0].Type.String(),
-						suffix,
-					))
+					cmd.Println(fmt.Sprintf("migration[%d]: Started %q", m.ID(), m.Name()))
 				}),
 				migrations.UpgradeOnComplete(func(m *migrations.Migration) {
 					cmd.Println(fmt.Sprintf(
@@ -61,19 +96,102 @@ func newUpgrade() *cobra.Command {
 					))
 				}),
 			)
-			if err != nil {
+
+			err = ms.Upgrade(cmd.Context(), opts...)
+			if errors.Is(err, migrations.ErrOutOfOrder) {
+				return fmt.Errorf("%w, use --%s to apply them", err, flagAllowOutOfOrder)
+			} else if errors.Is(err, migrations.ErrPendingOperation) {
+				return fmt.Errorf("%w, use \"%s operations wait\" to complete it", err, cmd.Root().Name())
+			} else if err != nil {
 				return err
 			}
 
+			if operation != "" {
+				cmd.Println(fmt.Sprintf(
+					"Use \"%s operations wait\" to complete the migration",
+					cmd.Root().Name(),
+				))
+				return nil
+			}
+
 			cmd.Println(fmt.Sprintf(
 				"Done at migration %d %s",
 				ms.LatestID(),
 				displayDuration(upgradeStartTime),
 			))
 
+			dumpSchema, err := cmd.Flags().GetBool(flagDumpSchema)
+			if err != nil {
+				return err
+			}
+
+			if dumpSchema {
+				err = ms.DumpSchema(cmd.Context())
+				if err != nil {
+					return err
+				}
+
+				cmd.Println(fmt.Sprintf("Schema written to %s", ms.SchemaPath()))
+			}
+
 			return nil
 		},
 	}
 
+	cmd.Flags().BoolP(flagAllowOutOfOrder, "", false, "run unapplied migrations older than the current migration")
+	setupEnvFlag(cmd)
+	cmd.Flags().BoolP(flagBackup, "", false, "back up the database before the first pending migration")
+	cmd.Flags().BoolP(flagNoWait, "", false, "exit after submitting the final DDL statements of a migration")
+	cmd.Flags().BoolP(flagDumpSchema, "", false, "write the resulting schema to the schema file")
+
 	return cmd
 }
+
+// upgradeOutputOptions returns the upgrade options printing the statements
+// run by a migration.
+func upgradeOutputOptions(cmd *cobra.Command) []migrations.UpgradeOption {
+	return []migrations.UpgradeOption{
+		migrations.UpgradeOnBatch(func(m *migrations.Migration, batch *migrations.Batch) {
+			var suffix string
+
+			if len(batch.Statements) != 1 {
+				suffix = "s"
+			}
+
+			if batch.FileDescriptorSet != "" {
+				suffix += fmt.Sprintf(" with file descriptor set %q",
+					batch.FileDescriptorSet)
+			}
+
+			cmd.Println(fmt.Sprintf(
+				"migration[%d]: Running %d %s statement%s",
+				m.ID(),
+				len(batch.Statements),
+				batch.Statements[0].Type.String(),
+				suffix,
+			))
+		}),
+		migrations.UpgradeOnWhen(func(m *migrations.Migration, pos int, result bool) {
+			action := "running"
+			if !result {
+				action = "skipping"
+			}
+
+			cmd.Println(fmt.Sprintf(
+				"migration[%d]: Condition for upgrade[%d] is %t, %s statement",
+				m.ID(),
+				pos,
+				result,
+				action,
+			))
+		}),
+		migrations.UpgradeOnProgress(func(m *migrations.Migration, s *jimmyv1.Statement, iteration int, rowCount int64) {
+			cmd.Println(fmt.Sprintf(
+				"migration[%d]: Batch %d affected %d rows",
+				m.ID(),
+				iteration,
+				rowCount,
+			))
+		}),
+	}
+}
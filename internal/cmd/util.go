@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -23,11 +24,38 @@ func args(checkArgs ...string) cobra.PositionalArgs {
 	}
 }
 
+func variadicArgs(checkArgs ...string) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(checkArgs) > len(args) {
+			return fmt.Errorf("%s required", checkArgs[len(args)])
+		}
+		return nil
+	}
+}
+
 func flagSet(cmd *cobra.Command, name string) bool {
 	flag := cmd.Flag(name)
 	return flag != nil && flag.Changed
 }
 
+// firstLine returns the first line of a SQL statement, marking whether
+// it was truncated.
+func firstLine(sql string) string {
+	line, _, found := strings.Cut(strings.TrimSpace(sql), "\n")
+	if found {
+		line += " ..."
+	}
+	return line
+}
+
 func displayDuration(t time.Time) string {
 	return fmt.Sprintf("in %s", time.Since(t).Round(time.Millisecond))
 }
+
+func joinIDs(ids []int) string {
+	s := make([]string, 0, len(ids))
+	for _, id := range ids {
+		s = append(s, strconv.Itoa(id))
+	}
+	return strings.Join(s, ", ")
+}
@@ -1,11 +1,38 @@
 package constants
 
+import (
+	"time"
+)
+
 const (
 	AppName         = "jimmy"
 	FileExt         = ".yaml"
 	ConfigFile      = ".jimmy" + FileExt
 	MigrationsPath  = "./migrations"
 	MigrationsTable = "migrations"
+	SchemaFile      = "./schema.sql"
+	SchemaProtoExt  = ".pb"
+	SeedsPath       = "./seeds"
+	SeedsTableExt   = "_seeds"
+
+	MaxMutationCells = 20_000
+	MaxMutationBytes = 16 << 20
+
+	EmulatorInstanceConfig = "emulator-config"
+	ProcessingUnits        = 100
+	BackupRetention        = 7 * 24 * time.Hour
+	BackupRestoreWindow    = time.Hour
+	VersionRetentionPeriod = time.Hour
+
+	BatchSize      = 1000
+	BatchSizeParam = "batch_size"
+
+	TimestampIDFormat = "20060102150405"
+
+	ImportGolangMigrate = "golang-migrate"
+	ImportLiquibase     = "liquibase"
+	ImportWrench        = "wrench"
+	ImportVersionTable  = "SchemaMigrations"
 
 	EnvEmulatorHost        = "SPANNER_EMULATOR_HOST"
 	EnvEmulatorHostDefault = "127.0.0.1:9010"
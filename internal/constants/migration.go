@@ -1,22 +1,73 @@
 package constants
 
+const SelectOne = `SELECT 1`
+
 const SelectMigrationsTable = `
 SELECT 1
 FROM information_schema.tables
 WHERE table_schema = @tableSchema AND table_name = @tableName
 `
 
-const SelectMigration = `
-SELECT id, complete_time IS NOT NULL
-FROM %s
-ORDER BY id DESC
-LIMIT 1
-`
-
 const CreateMigrationTable = `
 CREATE TABLE IF NOT EXISTS %s (
   id INT64 NOT NULL,
   start_time TIMESTAMP NOT NULL OPTIONS (allow_commit_timestamp=true),
-  complete_time TIMESTAMP OPTIONS (allow_commit_timestamp=true)
+  complete_time TIMESTAMP OPTIONS (allow_commit_timestamp=true),
+  operation STRING(MAX),
+  backup STRING(MAX),
+  restore_time TIMESTAMP
 ) PRIMARY KEY (id)
 `
+
+const AddMigrationOperationColumn = `
+ALTER TABLE %s ADD COLUMN operation STRING(MAX)
+`
+
+const AddMigrationBackupColumn = `
+ALTER TABLE %s ADD COLUMN backup STRING(MAX)
+`
+
+const AddMigrationRestoreTimeColumn = `
+ALTER TABLE %s ADD COLUMN restore_time TIMESTAMP
+`
+
+const SelectMigrations = `
+SELECT id, complete_time IS NOT NULL, %s
+FROM %s
+ORDER BY id
+`
+
+const SelectMigrationsNoOperation = "CAST(NULL AS STRING)"
+
+const SelectImportVersion = `
+SELECT Version, Dirty
+FROM %s
+`
+
+const SelectColumns = `
+SELECT column_name, spanner_type
+FROM information_schema.columns
+WHERE table_schema = @tableSchema AND table_name = @tableName
+ORDER BY ordinal_position
+`
+
+const CreateSeedsTable = `
+CREATE TABLE IF NOT EXISTS %s (
+  path STRING(MAX) NOT NULL,
+  checksum STRING(MAX) NOT NULL,
+  update_time TIMESTAMP NOT NULL OPTIONS (allow_commit_timestamp=true)
+) PRIMARY KEY (path)
+`
+
+const SelectSeeds = `
+SELECT path, checksum
+FROM %s
+`
+
+const SelectDatabaseOptions = `
+SELECT option_name, option_value
+FROM information_schema.database_options
+WHERE schema_name = ''
+`
+
+const AlterDatabaseOptions = "ALTER DATABASE `%s` SET OPTIONS (%s)"
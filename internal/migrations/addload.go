@@ -0,0 +1,71 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/silas/jimmy/internal/constants"
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
+)
+
+type AddLoadInput struct {
+	ID    int
+	Path  string
+	Table string
+	Env   jimmyv1.Environment
+}
+
+// AddLoad copies a CSV or newline-delimited JSON file next to the migration
+// and adds a statement loading it into a table.
+func (ms *Migrations) AddLoad(_ context.Context, input AddLoadInput) error {
+	m, err := ms.Get(input.ID)
+	if err != nil {
+		return err
+	}
+
+	switch filepath.Ext(input.Path) {
+	case ".csv", ".ndjson", ".jsonl":
+	default:
+		return fmt.Errorf("%q isn't a CSV or newline-delimited JSON file", input.Path)
+	}
+
+	table := input.Table
+	if table == "" {
+		table = seedTable(input.Path)
+	}
+
+	b, err := os.ReadFile(input.Path)
+	if err != nil {
+		return err
+	}
+
+	_, err = readRows(input.Path, b)
+	if err != nil {
+		return err
+	}
+
+	fileName := fmt.Sprintf(
+		"%s_%s",
+		strings.TrimSuffix(m.FileName(), constants.FileExt),
+		filepath.Base(input.Path),
+	)
+
+	err = os.WriteFile(filepath.Join(filepath.Dir(m.Path()), fileName), b, 0644)
+	if err != nil {
+		return err
+	}
+
+	m.data.Upgrade = append(m.data.Upgrade, &jimmyv1.Statement{
+		Env:  input.Env,
+		Type: jimmyv1.Type_LOAD,
+		Load: &jimmyv1.LoadOptions{
+			File:  fileName,
+			Table: table,
+		},
+	})
+
+	return Marshal(m.Path(), m.data)
+}
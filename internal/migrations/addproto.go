@@ -2,40 +2,117 @@ package migrations
 
 import (
 	"context"
-	"fmt"
-	"os"
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
 
-	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/descriptorpb"
+
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
 )
 
 type AddProtoInput struct {
-	ID   int
-	Name string
-	Path string
+	ID          int
+	Name        string
+	Paths       []string
+	ImportPaths []string
+	Types       []string
+	Bundle      bool
+
+	// Prune deletes bundled types that aren't in the file descriptor set.
+	Prune bool
 }
 
-func (ms *Migrations) AddProto(_ context.Context, input AddProtoInput) error {
+func (ms *Migrations) AddProto(ctx context.Context, input AddProtoInput) error {
 	m, err := ms.Get(input.ID)
 	if err != nil {
 		return err
 	}
 
-	err = checkFile(input.Path, "proto")
-	if err != nil {
-		return err
+	if len(input.Paths) == 0 {
+		return errors.New("path is required")
 	}
 
-	b, err := os.ReadFile(input.Path)
-	if err != nil {
-		return err
+	var fileDescriptorSet *descriptorpb.FileDescriptorSet
+
+	if isProtoSource(input.Paths[0]) {
+		for _, path := range input.Paths {
+			if !isProtoSource(path) {
+				return errors.New("proto source files can't be combined with a file descriptor set")
+			}
+		}
+
+		fileDescriptorSet, err = compileProto(ctx, input.Paths, input.ImportPaths)
+		if err != nil {
+			return err
+		}
+	} else {
+		if len(input.Paths) > 1 {
+			return errors.New("only one file descriptor set can be added at a time")
+		}
+
+		fileDescriptorSet, err = readFileDescriptorSet(input.Paths[0])
+		if err != nil {
+			return err
+		}
 	}
 
-	fileDescriptorSet := &descriptorpb.FileDescriptorSet{}
+	types := input.Types
+	if len(types) == 0 {
+		types = m.bundleTypes(input.Name)
+	}
 
-	err = proto.Unmarshal(b, fileDescriptorSet)
-	if err != nil {
-		return fmt.Errorf("failed to unmarshal %q file descriptor set: %w", input.Path, err)
+	if len(types) > 0 {
+		fileDescriptorSet, err = filterFileDescriptorSet(fileDescriptorSet, types)
+		if err != nil {
+			return err
+		}
+	}
+
+	if input.Bundle {
+		bundle, err := ms.protoBundle(m.ID())
+		if err != nil {
+			return err
+		}
+
+		// replace the bundle statements from a previous add of this file
+		// descriptor set
+		m.data.Upgrade = slices.DeleteFunc(m.data.Upgrade, func(s *jimmyv1.Statement) bool {
+			return s.GetFileDescriptorSet() == input.Name && isProtoDDL(s.Sql)
+		})
+
+		m.applyProtoBundle(bundle)
+
+		if len(types) == 0 {
+			types = bundleableTypes(fileDescriptorSet)
+		}
+
+		if len(types) == 0 {
+			return errors.New("no messages or enums found for proto bundle")
+		}
+
+		statement, err := ms.newStatement(
+			protoBundleSQL(bundle, types, input.Prune),
+			jimmyv1.Environment_ALL,
+			"",
+			jimmyv1.Type_DDL,
+		)
+		if err != nil {
+			return err
+		}
+
+		statement.FileDescriptorSet = Ref(input.Name)
+
+		// the bundle must exist before statements using its types
+		pos := slices.IndexFunc(m.data.Upgrade, func(s *jimmyv1.Statement) bool {
+			return referencesProtoTypes(s.Sql, types)
+		})
+		if pos < 0 {
+			pos = len(m.data.Upgrade)
+		}
+
+		m.data.Upgrade = slices.Insert(m.data.Upgrade, pos, statement)
 	}
 
 	if m.data.FileDescriptorSets == nil {
@@ -55,3 +132,20 @@ func (ms *Migrations) AddProto(_ context.Context, input AddProtoInput) error {
 
 	return nil
 }
+
+// referencesProtoTypes returns whether a statement contains any of the
+// fully-qualified type names.
+func referencesProtoTypes(sql string, types []string) bool {
+	for _, t := range types {
+		pattern := `(?:^|[^\w.])` + regexp.QuoteMeta(strings.TrimPrefix(t, ".")) + `(?:[^\w.]|$)`
+		if regexp.MustCompile(pattern).MatchString(sql) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isProtoSource(path string) bool {
+	return strings.HasSuffix(path, ".proto")
+}
@@ -2,16 +2,22 @@ package migrations
 
 import (
 	"context"
+	"fmt"
+	"slices"
 
 	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
 )
 
 type AddUpgradeInput struct {
-	ID         int
-	SQL        string
-	Env        jimmyv1.Environment
-	TemplateID string
-	Type       jimmyv1.Type
+	ID          int
+	SQL         string
+	Env         jimmyv1.Environment
+	TemplateID  string
+	Type        jimmyv1.Type
+	Envs        []string
+	ExcludeEnvs []string
+	When        string
+	Batched     *jimmyv1.BatchedOptions
 }
 
 func (ms *Migrations) AddUpgrade(_ context.Context, input AddUpgradeInput) error {
@@ -25,6 +31,26 @@ func (ms *Migrations) AddUpgrade(_ context.Context, input AddUpgradeInput) error
 		return err
 	}
 
+	err = ms.checkEnvs(append(slices.Clone(input.Envs), input.ExcludeEnvs...)...)
+	if err != nil {
+		return err
+	}
+
+	statement.Envs = input.Envs
+	statement.ExcludeEnvs = input.ExcludeEnvs
+
+	if input.When != "" {
+		statement.When = &input.When
+	}
+
+	if input.Batched != nil {
+		if statement.Type != jimmyv1.Type_BATCHED_DML {
+			return fmt.Errorf("batched options require %s type", jimmyv1.Type_BATCHED_DML)
+		}
+
+		statement.Batched = input.Batched
+	}
+
 	m.data.Upgrade = append(m.data.Upgrade, statement)
 
 	err = Marshal(m.Path(), m.data)
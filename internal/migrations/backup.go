@@ -0,0 +1,219 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"cloud.google.com/go/spanner/admin/database/apiv1/databasepb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/silas/jimmy/internal/constants"
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
+)
+
+// BackupAdmin creates database backups.
+type BackupAdmin interface {
+	// CreateBackup backs up the database as of the version time and
+	// returns the backup name once the backup is ready.
+	CreateBackup(ctx context.Context, database, backupID string, versionTime, expireTime time.Time) (string, error)
+}
+
+// Backup is the restore point recorded before a migration.
+type Backup struct {
+	// Name is the name of the backup, empty when only a restore timestamp
+	// was recorded.
+	Name string
+
+	// VersionTime is the time the database can be restored to.
+	VersionTime time.Time
+}
+
+type OnMigrationBackup func(m *Migration, backup *Backup)
+
+type databaseBackupAdmin struct {
+	ms *Migrations
+}
+
+func (a *databaseBackupAdmin) CreateBackup(
+	ctx context.Context,
+	database, backupID string,
+	versionTime, expireTime time.Time,
+) (string, error) {
+	dbAdmin, err := a.ms.DatabaseAdmin(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	op, err := dbAdmin.CreateBackup(ctx, &databasepb.CreateBackupRequest{
+		Parent:   a.ms.InstanceName(),
+		BackupId: backupID,
+		Backup: &databasepb.Backup{
+			Database:    database,
+			VersionTime: timestamppb.New(versionTime),
+			ExpireTime:  timestamppb.New(expireTime),
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// the migration must not start before the backup exists
+	backup, err := op.Wait(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return backup.GetName(), nil
+}
+
+// SetBackupAdmin sets the client used to create backups.
+func (ms *Migrations) SetBackupAdmin(admin BackupAdmin) {
+	ms.backupAdmin = admin
+}
+
+// RestoreCommand returns a command that restores the database to the
+// backup.
+func (ms *Migrations) RestoreCommand(backup *Backup) string {
+	if backup.Name != "" {
+		return fmt.Sprintf(
+			"gcloud spanner databases restore --project=%s --source-instance=%s --source-backup=%s --destination-instance=%s --destination-database=%s-restore",
+			ms.Config.ProjectId,
+			ms.Config.InstanceId,
+			path.Base(backup.Name),
+			ms.Config.InstanceId,
+			ms.Config.DatabaseId,
+		)
+	}
+
+	return fmt.Sprintf(
+		"gcloud spanner backups create %s-restore --project=%s --instance=%s --database=%s --version-time=%s --retention-period=1d",
+		ms.Config.DatabaseId,
+		ms.Config.ProjectId,
+		ms.Config.InstanceId,
+		ms.Config.DatabaseId,
+		backup.VersionTime.UTC().Format(time.RFC3339Nano),
+	)
+}
+
+// backup records a restore point for a migration, creating a backup unless
+// only timestamps are configured or the emulator is used.
+func (ms *Migrations) backup(ctx context.Context, m *Migration) (*Backup, error) {
+	versionTime, err := ms.executor().ReadTimestamp(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get restore timestamp: %w", err)
+	}
+
+	return ms.createBackup(ctx, m, versionTime)
+}
+
+func (ms *Migrations) createBackup(ctx context.Context, m *Migration, versionTime time.Time) (*Backup, error) {
+	backup := &Backup{VersionTime: versionTime}
+
+	if ms.emulator {
+		return backup, nil
+	}
+
+	if ms.Config.GetBackup().GetTimestampOnly() {
+		options, err := ms.executor().DatabaseOptions(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get database options: %w", err)
+		}
+
+		err = checkRestoreWindow(ms.Config.GetBackup(), options)
+		if err != nil {
+			return nil, err
+		}
+
+		return backup, nil
+	}
+
+	retention := constants.BackupRetention
+	if ms.Config.GetBackup().GetRetention() != nil {
+		retention = ms.Config.GetBackup().GetRetention().AsDuration()
+	}
+
+	admin := ms.backupAdmin
+	if admin == nil {
+		admin = &databaseBackupAdmin{ms: ms}
+	}
+
+	backupID := fmt.Sprintf(
+		"%s-%d-%s",
+		ms.Config.DatabaseId,
+		m.ID(),
+		versionTime.UTC().Format("20060102150405"),
+	)
+
+	name, err := admin.CreateBackup(ctx, ms.DatabaseName(), backupID, versionTime, versionTime.Add(retention))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backup: %w", err)
+	}
+
+	backup.Name = name
+
+	return backup, nil
+}
+
+// checkRestoreWindow returns an error when a restore timestamp would leave
+// the version retention period of the database before the end of the
+// restore window.
+func checkRestoreWindow(config *jimmyv1.Backup, options map[string]string) error {
+	window := constants.BackupRestoreWindow
+	if config.GetRestoreWindow() != nil {
+		window = config.GetRestoreWindow().AsDuration()
+	}
+
+	retention := constants.VersionRetentionPeriod
+
+	if value := options["version_retention_period"]; value != "" {
+		var err error
+
+		retention, err = parseRetentionPeriod(value)
+		if err != nil {
+			return fmt.Errorf("invalid version_retention_period %q: %w", value, err)
+		}
+	}
+
+	if window > retention {
+		return fmt.Errorf(
+			"backup restore window %s exceeds the version_retention_period %s of the database, "+
+				"increase version_retention_period or disable timestamp_only",
+			window,
+			retention,
+		)
+	}
+
+	return nil
+}
+
+// parseRetentionPeriod parses a version retention period such as 1h or 7d.
+func parseRetentionPeriod(value string) (time.Duration, error) {
+	if days, found := strings.CutSuffix(value, "d"); found {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	return time.ParseDuration(value)
+}
+
+// setBackup records the restore point of a migration.
+func (ms *Migrations) setBackup(ctx context.Context, id int, backup *Backup) error {
+	err := ms.executor().UpdateMigration(ctx, ms.Config.Table, id, map[string]any{
+		"backup":       spanner.NullString{StringVal: backup.Name, Valid: backup.Name != ""},
+		"restore_time": backup.VersionTime,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record backup: %w", err)
+	}
+
+	return nil
+}
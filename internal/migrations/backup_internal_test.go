@@ -0,0 +1,123 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
+)
+
+type fakeBackupAdmin struct {
+	database    string
+	backupID    string
+	versionTime time.Time
+	expireTime  time.Time
+	err         error
+}
+
+func (f *fakeBackupAdmin) CreateBackup(
+	ctx context.Context,
+	database, backupID string,
+	versionTime, expireTime time.Time,
+) (string, error) {
+	f.database = database
+	f.backupID = backupID
+	f.versionTime = versionTime
+	f.expireTime = expireTime
+
+	if f.err != nil {
+		return "", f.err
+	}
+
+	return fmt.Sprintf("projects/test/instances/test/backups/%s", backupID), nil
+}
+
+// optionsExecutor only returns database options.
+type optionsExecutor struct {
+	Executor
+
+	options map[string]string
+}
+
+func (e *optionsExecutor) DatabaseOptions(_ context.Context) (map[string]string, error) {
+	return e.options, nil
+}
+
+func TestCreateBackup(t *testing.T) {
+	ctx := context.Background()
+
+	ms := New("")
+	ms.emulator = false
+	ms.Config = &jimmyv1.Config{
+		ProjectId:  "test",
+		InstanceId: "test",
+		DatabaseId: "app",
+	}
+
+	admin := &fakeBackupAdmin{}
+	ms.SetBackupAdmin(admin)
+
+	m := newMigration(ms, 3, "00003_test.yaml", &jimmyv1.Migration{})
+	versionTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	backup, err := ms.createBackup(ctx, m, versionTime)
+	require.NoError(t, err)
+	require.Equal(t, "projects/test/instances/test/backups/app-3-20240102030405", backup.Name)
+	require.Equal(t, versionTime, backup.VersionTime)
+	require.Equal(t, "projects/test/instances/test/databases/app", admin.database)
+	require.Equal(t, versionTime, admin.versionTime)
+	require.Equal(t, versionTime.Add(7*24*time.Hour), admin.expireTime)
+	require.Equal(t,
+		"gcloud spanner databases restore --project=test --source-instance=test --source-backup=app-3-20240102030405 --destination-instance=test --destination-database=app-restore",
+		ms.RestoreCommand(backup),
+	)
+
+	ms.Config.Backup = &jimmyv1.Backup{Retention: durationpb.New(time.Hour)}
+
+	_, err = ms.createBackup(ctx, m, versionTime)
+	require.NoError(t, err)
+	require.Equal(t, versionTime.Add(time.Hour), admin.expireTime)
+
+	admin.err = errors.New("unavailable")
+
+	_, err = ms.createBackup(ctx, m, versionTime)
+	require.ErrorContains(t, err, "failed to create backup: unavailable")
+
+	admin.backupID = ""
+	ms.Config.Backup.TimestampOnly = true
+
+	executor := &optionsExecutor{options: map[string]string{}}
+	ms.SetExecutor(executor)
+
+	backup, err = ms.createBackup(ctx, m, versionTime)
+	require.NoError(t, err)
+	require.Empty(t, backup.Name)
+	require.Empty(t, admin.backupID)
+	require.Equal(t,
+		"gcloud spanner backups create app-restore --project=test --instance=test --database=app --version-time=2024-01-02T03:04:05Z --retention-period=1d",
+		ms.RestoreCommand(backup),
+	)
+
+	// the restore timestamp must stay within the version retention period
+	ms.Config.Backup.RestoreWindow = durationpb.New(2 * time.Hour)
+
+	_, err = ms.createBackup(ctx, m, versionTime)
+	require.EqualError(t, err, "backup restore window 2h0m0s exceeds the version_retention_period 1h0m0s of the database, "+
+		"increase version_retention_period or disable timestamp_only")
+
+	executor.options["version_retention_period"] = "3d"
+
+	_, err = ms.createBackup(ctx, m, versionTime)
+	require.NoError(t, err)
+
+	executor.options["version_retention_period"] = "90m"
+
+	_, err = ms.createBackup(ctx, m, versionTime)
+	require.ErrorContains(t, err, "version_retention_period 1h30m0s")
+}
@@ -0,0 +1,67 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/spanner"
+
+	"github.com/silas/jimmy/internal/constants"
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
+)
+
+// runBatchedDML runs a DML statement in its own transaction until it
+// doesn't affect any rows.
+func (ms *Migrations) runBatchedDML(
+	ctx context.Context,
+	m *Migration,
+	s *jimmyv1.Statement,
+	onProgress OnMigrationProgress,
+) error {
+	options := s.GetBatched()
+
+	batchSize := options.GetSize()
+	if batchSize == 0 {
+		batchSize = constants.BatchSize
+	}
+
+	pause := options.GetPause().AsDuration()
+	maxIterations := int(options.GetMaxIterations())
+
+	stmt := spanner.Statement{
+		SQL: s.Sql,
+		Params: map[string]any{
+			constants.BatchSizeParam: batchSize,
+		},
+	}
+
+	for iteration := 1; ; iteration++ {
+		if maxIterations > 0 && iteration > maxIterations {
+			return fmt.Errorf("migration %d batched DML affected rows after %d iterations", m.ID(), maxIterations)
+		}
+
+		rowCounts, err := ms.executor().BatchUpdate(ctx, []spanner.Statement{stmt})
+		if err != nil {
+			return err
+		}
+
+		rowCount := rowCounts[0]
+
+		if onProgress != nil {
+			onProgress(m, s, iteration, rowCount)
+		}
+
+		if rowCount == 0 {
+			return nil
+		}
+
+		if pause > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(pause):
+			}
+		}
+	}
+}
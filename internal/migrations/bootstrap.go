@@ -3,11 +3,7 @@ package migrations
 import (
 	"context"
 	"errors"
-	"fmt"
-	"strings"
 
-	"cloud.google.com/go/spanner/admin/database/apiv1/databasepb"
-	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/descriptorpb"
 
 	"github.com/silas/jimmy/internal/constants"
@@ -24,38 +20,20 @@ func (ms *Migrations) Bootstrap(ctx context.Context, input BootstrapInput) (*Mig
 		slug = "init"
 	}
 
-	err := ms.ensureAll(ctx)
+	schema, err := ms.getSchema(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	var upgrade []*jimmyv1.Statement
-
-	dbAdmin, err := ms.DatabaseAdmin(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	ddl, err := dbAdmin.GetDatabaseDdl(ctx, &databasepb.GetDatabaseDdlRequest{
-		Database: ms.DatabaseName(),
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	if len(ddl.Statements) == 0 {
+	if len(schema.Statements) == 0 {
 		return nil, errors.New("no statements")
 	}
 
-	hasFileDescriptorSet := len(ddl.ProtoDescriptors) > 0
-
-	migrationTableDDL := fmt.Sprintf("CREATE TABLE %s (", ms.Config.Table)
+	var upgrade []*jimmyv1.Statement
 
-	for _, sql := range ddl.Statements {
-		if strings.Contains(sql, migrationTableDDL) {
-			continue
-		}
+	hasFileDescriptorSet := schema.FileDescriptorSet != nil
 
+	for _, sql := range schema.Statements {
 		statement, err := ms.newStatement(
 			sql,
 			jimmyv1.Environment_ALL,
@@ -76,18 +54,11 @@ func (ms *Migrations) Bootstrap(ctx context.Context, input BootstrapInput) (*Mig
 	data := &jimmyv1.Migration{
 		Upgrade:            upgrade,
 		FileDescriptorSets: map[string]*descriptorpb.FileDescriptorSet{},
-		SquashId:           Ref[int32](0),
+		SquashId:           Ref[int64](0),
 	}
 
 	if hasFileDescriptorSet {
-		fileDescriptorSet := &descriptorpb.FileDescriptorSet{}
-
-		err = proto.Unmarshal(ddl.ProtoDescriptors, fileDescriptorSet)
-		if err != nil {
-			return nil, fmt.Errorf("failed to unmarshal file descriptor set: %w", err)
-		}
-
-		data.FileDescriptorSets[constants.UpgradeFileDescriptorSet] = fileDescriptorSet
+		data.FileDescriptorSets[constants.UpgradeFileDescriptorSet] = schema.FileDescriptorSet
 	}
 
 	return ms.create(slug, data)
@@ -3,18 +3,26 @@ package migrations
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strconv"
+	"time"
 
 	"github.com/silas/jimmy/internal/constants"
 	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
 )
 
 type CreateInput struct {
-	Name       string
-	SQL        string
-	Env        jimmyv1.Environment
-	TemplateID string
-	Type       jimmyv1.Type
-	SquashID   int
+	Name         string
+	SQL          string
+	Env          jimmyv1.Environment
+	TemplateID   string
+	Type         jimmyv1.Type
+	Envs         []string
+	ExcludeEnvs  []string
+	When         string
+	Batched      *jimmyv1.BatchedOptions
+	SquashID     int
+	BackupBefore bool
 }
 
 func (ms *Migrations) Create(ctx context.Context, input CreateInput) (*Migration, error) {
@@ -28,6 +36,26 @@ func (ms *Migrations) Create(ctx context.Context, input CreateInput) (*Migration
 		return nil, err
 	}
 
+	err = ms.checkEnvs(append(slices.Clone(input.Envs), input.ExcludeEnvs...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	statement.Envs = input.Envs
+	statement.ExcludeEnvs = input.ExcludeEnvs
+
+	if input.When != "" {
+		statement.When = &input.When
+	}
+
+	if input.Batched != nil {
+		if statement.Type != jimmyv1.Type_BATCHED_DML {
+			return nil, fmt.Errorf("batched options require %s type", jimmyv1.Type_BATCHED_DML)
+		}
+
+		statement.Batched = input.Batched
+	}
+
 	slug := Slugify(input.Name)
 	if slug == "" {
 		slug = Slugify(input.TemplateID)
@@ -37,7 +65,8 @@ func (ms *Migrations) Create(ctx context.Context, input CreateInput) (*Migration
 	}
 
 	m := &jimmyv1.Migration{
-		Upgrade: []*jimmyv1.Statement{statement},
+		Upgrade:      []*jimmyv1.Statement{statement},
+		BackupBefore: input.BackupBefore,
 	}
 	if input.SquashID > 0 {
 		sm, err := ms.Get(input.SquashID)
@@ -52,19 +81,19 @@ func (ms *Migrations) Create(ctx context.Context, input CreateInput) (*Migration
 			)
 		}
 
-		m.SquashId = Ref(int32(sm.ID()))
+		m.SquashId = Ref(int64(sm.ID()))
 	}
 
 	return ms.create(slug, m)
 }
 
 func (ms *Migrations) create(slug string, data *jimmyv1.Migration) (*Migration, error) {
-	id := ms.latestID + 1
+	id := ms.nextID()
 
 	m := newMigration(
 		ms,
 		id,
-		fmt.Sprintf("%05d_%s%s", id, slug, constants.FileExt),
+		ms.fileName(id, slug),
 		data,
 	)
 
@@ -78,6 +107,25 @@ func (ms *Migrations) create(slug string, data *jimmyv1.Migration) (*Migration,
 	return m, err
 }
 
+// nextID returns an unused ID for a new migration.
+func (ms *Migrations) nextID() int {
+	id := ms.latestID + 1
+
+	if ms.Config.GetIdScheme() == jimmyv1.IdScheme_TIMESTAMP {
+		id, _ = strconv.Atoi(time.Now().UTC().Format(constants.TimestampIDFormat))
+	}
+
+	for ms.migrations[id] != nil {
+		id++
+	}
+
+	return id
+}
+
+func (ms *Migrations) fileName(id int, slug string) string {
+	return fmt.Sprintf("%05d_%s%s", id, slug, constants.FileExt)
+}
+
 func (ms *Migrations) setMigration(m *Migration) {
 	ms.migrations[m.id] = m
 	ms.latestID = max(ms.latestID, m.id)
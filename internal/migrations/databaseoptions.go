@@ -0,0 +1,107 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/silas/jimmy/internal/constants"
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
+)
+
+type DatabaseOption struct {
+	Name    string
+	Current string
+	Desired string
+
+	// literal is the desired value as a SQL literal.
+	literal string
+}
+
+func (o *DatabaseOption) String() string {
+	current := o.Current
+	if current == "" {
+		current = "(unset)"
+	}
+	return fmt.Sprintf("%s: %s -> %s", o.Name, current, o.Desired)
+}
+
+// DatabaseOptionsDrift returns the configured database options that differ
+// from the database.
+func (ms *Migrations) DatabaseOptionsDrift(ctx context.Context) ([]*DatabaseOption, error) {
+	if ms.Config.GetDatabaseOptions() == nil {
+		return nil, nil
+	}
+
+	current, err := ms.executor().DatabaseOptions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database options: %w", err)
+	}
+
+	return databaseOptionsDrift(ms.Config.GetDatabaseOptions(), current), nil
+}
+
+// applyDatabaseOptions sets the configured database options that differ
+// from the database.
+func (ms *Migrations) applyDatabaseOptions(ctx context.Context) ([]*DatabaseOption, error) {
+	drift, err := ms.DatabaseOptionsDrift(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(drift) == 0 {
+		return nil, nil
+	}
+
+	err = ms.runDDL(ctx, alterDatabaseOptionsSQL(ms.Config.DatabaseId, drift))
+	if err != nil {
+		return nil, fmt.Errorf("failed to set database options: %w", err)
+	}
+
+	return drift, nil
+}
+
+func databaseOptionsDrift(options *jimmyv1.DatabaseOptions, current map[string]string) []*DatabaseOption {
+	var drift []*DatabaseOption
+
+	options.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		option := &DatabaseOption{
+			Name:    string(fd.Name()),
+			Current: current[string(fd.Name())],
+		}
+
+		if fd.Kind() == protoreflect.Int64Kind {
+			option.Desired = strconv.FormatInt(v.Int(), 10)
+			option.literal = option.Desired
+		} else {
+			option.Desired = v.String()
+			option.literal = strconv.Quote(option.Desired)
+		}
+
+		if !strings.EqualFold(option.Current, option.Desired) {
+			drift = append(drift, option)
+		}
+
+		return true
+	})
+
+	slices.SortFunc(drift, func(a, b *DatabaseOption) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return drift
+}
+
+func alterDatabaseOptionsSQL(database string, options []*DatabaseOption) string {
+	var values []string
+
+	for _, option := range options {
+		values = append(values, fmt.Sprintf("%s = %s", option.Name, option.literal))
+	}
+
+	return fmt.Sprintf(constants.AlterDatabaseOptions, database, strings.Join(values, ", "))
+}
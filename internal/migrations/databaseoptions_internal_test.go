@@ -0,0 +1,64 @@
+package migrations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
+)
+
+func TestDatabaseOptionsDrift(t *testing.T) {
+	options := &jimmyv1.DatabaseOptions{
+		VersionRetentionPeriod: proto.String("7d"),
+		OptimizerVersion:       proto.Int64(6),
+		DefaultTimeZone:        proto.String("America/New_York"),
+	}
+
+	drift := databaseOptionsDrift(options, map[string]string{
+		"version_retention_period": "1h",
+		"optimizer_version":        "6",
+	})
+	require.Len(t, drift, 2)
+	require.Equal(t, "default_time_zone: (unset) -> America/New_York", drift[0].String())
+	require.Equal(t, "version_retention_period: 1h -> 7d", drift[1].String())
+
+	require.Equal(t,
+		"ALTER DATABASE `test` SET OPTIONS (default_time_zone = \"America/New_York\", version_retention_period = \"7d\")",
+		alterDatabaseOptionsSQL("test", drift),
+	)
+
+	options.VersionRetentionPeriod = proto.String("1h")
+	options.DefaultTimeZone = nil
+	options.OptimizerVersion = proto.Int64(7)
+
+	drift = databaseOptionsDrift(options, map[string]string{
+		"version_retention_period": "1h",
+		"optimizer_version":        "6",
+	})
+	require.Len(t, drift, 1)
+	require.Equal(t, "ALTER DATABASE `test` SET OPTIONS (optimizer_version = 7)", alterDatabaseOptionsSQL("test", drift))
+}
+
+func TestSetTarget_DatabaseOptions(t *testing.T) {
+	ms := &Migrations{
+		Config: &jimmyv1.Config{
+			DatabaseOptions: &jimmyv1.DatabaseOptions{
+				VersionRetentionPeriod: proto.String("1h"),
+				OptimizerVersion:       proto.Int64(6),
+			},
+			Targets: map[string]*jimmyv1.Target{
+				"prod": {
+					DatabaseOptions: &jimmyv1.DatabaseOptions{
+						VersionRetentionPeriod: proto.String("7d"),
+					},
+				},
+			},
+		},
+	}
+
+	require.NoError(t, ms.SetTarget("prod"))
+	require.Equal(t, "7d", ms.Config.DatabaseOptions.GetVersionRetentionPeriod())
+	require.Equal(t, int64(6), ms.Config.DatabaseOptions.GetOptimizerVersion())
+}
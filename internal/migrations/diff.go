@@ -0,0 +1,604 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"cloud.google.com/go/spanner/spansql"
+
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
+)
+
+type DiffInput struct {
+	Path             string
+	Replay           bool
+	AllowDestructive bool
+}
+
+type CreateDiffInput struct {
+	Name             string
+	Path             string
+	Replay           bool
+	AllowDestructive bool
+}
+
+// Diff returns the DDL statements required to move the current schema to
+// the desired schema file.
+func (ms *Migrations) Diff(ctx context.Context, input DiffInput) ([]string, error) {
+	path := input.Path
+	if path == "" {
+		path = ms.SchemaPath()
+	}
+
+	err := checkFile(path, "schema")
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	desired, err := parseSchema(splitStatements(string(b)))
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", path, err)
+	}
+
+	var schema *Schema
+
+	if input.Replay {
+		err = ms.replay(ctx, func(ctx context.Context, rms *Migrations) error {
+			var err error
+			schema, err = rms.getSchema(ctx)
+			return err
+		})
+	} else {
+		schema, err = ms.getSchema(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := parseSchema(schema.Statements)
+	if err != nil {
+		return nil, fmt.Errorf("current schema: %w", err)
+	}
+
+	return diffSchema(current, desired, input.AllowDestructive)
+}
+
+func (ms *Migrations) CreateDiff(ctx context.Context, input CreateDiffInput) (*Migration, error) {
+	statements, err := ms.Diff(ctx, DiffInput{
+		Path:             input.Path,
+		Replay:           input.Replay,
+		AllowDestructive: input.AllowDestructive,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(statements) == 0 {
+		return nil, errors.New("no changes")
+	}
+
+	data := &jimmyv1.Migration{}
+
+	for _, sql := range statements {
+		statement, err := ms.newStatement(sql, jimmyv1.Environment_ALL, "", jimmyv1.Type_DDL)
+		if err != nil {
+			return nil, err
+		}
+
+		data.Upgrade = append(data.Upgrade, statement)
+	}
+
+	slug := Slugify(input.Name)
+	if slug == "" {
+		slug = "diff"
+	}
+
+	return ms.create(slug, data)
+}
+
+var (
+	diffPunctuation = regexp.MustCompile(`\s*([(),])\s*`)
+	diffDrop        = regexp.MustCompile(`^(?:ALTER\s+TABLE\s+\S+\s+)?DROP\s+(?:CONSTRAINT|COLUMN|TABLE|INDEX|SEARCH\s+INDEX|VECTOR\s+INDEX|VIEW|CHANGE\s+STREAM)\b`)
+)
+
+type schemaModel struct {
+	tables  map[spansql.ID]*spansql.CreateTable
+	indexes map[spansql.ID]*spansql.CreateIndex
+	views   map[spansql.ID]*spansql.CreateView
+	streams map[spansql.ID]*spansql.CreateChangeStream
+
+	// other holds the statements that aren't modeled, by normalized SQL,
+	// which can only be diffed when they're unchanged
+	other map[string]error
+}
+
+// normalizeSQL removes formatting differences from a statement.
+func normalizeSQL(sql string) string {
+	return diffPunctuation.ReplaceAllString(oneLine(sql), "$1")
+}
+
+func parseSchema(statements []string) (*schemaModel, error) {
+	model := &schemaModel{
+		tables:  map[spansql.ID]*spansql.CreateTable{},
+		indexes: map[spansql.ID]*spansql.CreateIndex{},
+		views:   map[spansql.ID]*spansql.CreateView{},
+		streams: map[spansql.ID]*spansql.CreateChangeStream{},
+		other:   map[string]error{},
+	}
+
+	for _, sql := range statements {
+		stmt, err := spansql.ParseDDLStmt(sql)
+		if err != nil {
+			model.other[normalizeSQL(sql)] = fmt.Errorf("failed to parse %q: %w", firstLine(sql), err)
+			continue
+		}
+
+		switch s := stmt.(type) {
+		case *spansql.CreateTable:
+			model.tables[s.Name] = s
+		case *spansql.CreateIndex:
+			model.indexes[s.Name] = s
+		case *spansql.CreateView:
+			s.OrReplace = false
+			model.views[s.Name] = s
+		case *spansql.CreateChangeStream:
+			model.streams[s.Name] = s
+		case *spansql.AlterTable:
+			table := model.tables[s.Name]
+			if table == nil {
+				return nil, fmt.Errorf("table %s not found: %s", s.Name, firstLine(sql))
+			}
+
+			switch alteration := s.Alteration.(type) {
+			case spansql.AddColumn:
+				table.Columns = append(table.Columns, alteration.Def)
+			case spansql.AddConstraint:
+				table.Constraints = append(table.Constraints, alteration.Constraint)
+			case spansql.AddRowDeletionPolicy:
+				table.RowDeletionPolicy = &alteration.RowDeletionPolicy
+			default:
+				model.other[normalizeSQL(sql)] = fmt.Errorf("unsupported statement: %s", firstLine(sql))
+			}
+		default:
+			model.other[normalizeSQL(sql)] = fmt.Errorf("unsupported statement: %s", firstLine(sql))
+		}
+	}
+
+	return model, nil
+}
+
+// diffSchema returns the statements to move from the current to the desired
+// schema, dropping objects before creating them and creating tables before
+// the objects that depend on them.
+func diffSchema(current, desired *schemaModel, allowDestructive bool) ([]string, error) {
+	var (
+		dropStreams     []string
+		dropViews       []string
+		dropConstraints []string
+		dropIndexes     []string
+		dropColumns     []string
+		dropTables      []string
+		createTables    []string
+		alterTables     []string
+		createIndexes   []string
+		addConstraints  []string
+		createViews     []string
+		alterStreams    []string
+		createStreams   []string
+	)
+
+	// dropped columns by table, including columns that are recreated
+	droppedColumns := map[spansql.ID]map[spansql.ID]bool{}
+
+	// unmodeled statements
+	for _, models := range [][2]*schemaModel{{current, desired}, {desired, current}} {
+		for _, sql := range slices.Sorted(maps.Keys(models[0].other)) {
+			if _, found := models[1].other[sql]; !found {
+				return nil, models[0].other[sql]
+			}
+		}
+	}
+
+	// change streams
+	for _, name := range sortedKeys(current.streams) {
+		if _, found := desired.streams[name]; !found {
+			dropStreams = append(dropStreams, spansql.DropChangeStream{Name: name}.SQL())
+		}
+	}
+
+	for _, name := range sortedKeys(desired.streams) {
+		want := desired.streams[name]
+
+		have, found := current.streams[name]
+		if !found {
+			createStreams = append(createStreams, want.SQL())
+			continue
+		}
+
+		var alterations []spansql.ChangeStreamAlteration
+
+		if watchSQL(have) != watchSQL(want) {
+			if want.WatchAllTables || len(want.Watch) > 0 {
+				alterations = append(alterations, spansql.AlterWatch{
+					WatchAllTables: want.WatchAllTables,
+					Watch:          want.Watch,
+				})
+			} else {
+				alterations = append(alterations, spansql.DropChangeStreamWatch{})
+			}
+		}
+
+		if have.Options.SQL() != want.Options.SQL() {
+			alterations = append(alterations, spansql.AlterChangeStreamOptions{
+				Options: want.Options,
+			})
+		}
+
+		// watching new tables must wait until they're created
+		late := slices.ContainsFunc(want.Watch, func(w spansql.WatchDef) bool {
+			_, found := current.tables[w.Table]
+			return !found
+		})
+
+		for _, alteration := range alterations {
+			sql := spansql.AlterChangeStream{Name: name, Alteration: alteration}.SQL()
+
+			if late {
+				alterStreams = append(alterStreams, sql)
+			} else {
+				dropStreams = append(dropStreams, sql)
+			}
+		}
+	}
+
+	// views
+	for _, name := range sortedKeys(current.views) {
+		if _, found := desired.views[name]; !found {
+			dropViews = append(dropViews, spansql.DropView{Name: name}.SQL())
+		}
+	}
+
+	for _, name := range sortedKeys(desired.views) {
+		want := desired.views[name]
+
+		have, found := current.views[name]
+		if found && have.SQL() == want.SQL() {
+			continue
+		}
+
+		view := *want
+		view.OrReplace = found
+
+		createViews = append(createViews, view.SQL())
+	}
+
+	// tables
+	var droppedTables []string
+
+	for _, name := range sortedKeys(current.tables) {
+		if _, found := desired.tables[name]; found {
+			continue
+		}
+
+		droppedTables = append(droppedTables, current.tables[name].SQL())
+	}
+
+	// drop child tables before their parents
+	droppedTables = sortDDL(droppedTables)
+	slices.Reverse(droppedTables)
+
+	for _, sql := range droppedTables {
+		match := ddlCreate.FindStringSubmatch(sql)
+
+		dropTables = append(dropTables, spansql.DropTable{Name: spansql.ID(match[2])}.SQL())
+	}
+
+	var newTables []string
+
+	for _, name := range sortedKeys(desired.tables) {
+		want := desired.tables[name]
+
+		have, found := current.tables[name]
+		if !found {
+			newTables = append(newTables, want.SQL())
+			continue
+		}
+
+		if keyPartsSQL(have.PrimaryKey) != keyPartsSQL(want.PrimaryKey) {
+			return nil, fmt.Errorf("changing the primary key of table %s isn't supported", name)
+		}
+
+		if interleaveSQL(have.Interleave) != interleaveSQL(want.Interleave) {
+			if have.Interleave == nil || want.Interleave == nil || have.Interleave.Parent != want.Interleave.Parent {
+				return nil, fmt.Errorf("changing the parent of table %s isn't supported", name)
+			}
+
+			alterTables = append(alterTables, spansql.AlterTable{
+				Name:       name,
+				Alteration: spansql.SetOnDelete{Action: want.Interleave.OnDelete},
+			}.SQL())
+		}
+
+		// columns
+		haveColumns := map[spansql.ID]spansql.ColumnDef{}
+		for _, c := range have.Columns {
+			haveColumns[c.Name] = c
+		}
+
+		wantColumns := map[spansql.ID]spansql.ColumnDef{}
+		for _, c := range want.Columns {
+			wantColumns[c.Name] = c
+		}
+
+		for _, c := range have.Columns {
+			if _, found := wantColumns[c.Name]; found {
+				continue
+			}
+
+			dropColumns = append(dropColumns, spansql.AlterTable{
+				Name:       name,
+				Alteration: spansql.DropColumn{Name: c.Name},
+			}.SQL())
+			dropColumn(droppedColumns, name, c.Name)
+		}
+
+		for _, c := range want.Columns {
+			hc, found := haveColumns[c.Name]
+			if !found {
+				alterTables = append(alterTables, spansql.AlterTable{
+					Name:       name,
+					Alteration: spansql.AddColumn{Def: c},
+				}.SQL())
+				continue
+			}
+
+			if hc.SQL() == c.SQL() {
+				continue
+			}
+
+			if exprSQL(hc.Generated) != exprSQL(c.Generated) {
+				dropColumns = append(dropColumns, spansql.AlterTable{
+					Name:       name,
+					Alteration: spansql.DropColumn{Name: c.Name},
+				}.SQL())
+				dropColumn(droppedColumns, name, c.Name)
+
+				alterTables = append(alterTables, spansql.AlterTable{
+					Name:       name,
+					Alteration: spansql.AddColumn{Def: c},
+				}.SQL())
+				continue
+			}
+
+			var alterations []spansql.ColumnAlteration
+
+			if hc.Type.SQL() != c.Type.SQL() || hc.NotNull != c.NotNull {
+				alterations = append(alterations, spansql.SetColumnType{
+					Type:    c.Type,
+					NotNull: c.NotNull,
+					Default: c.Default,
+				})
+			} else if exprSQL(hc.Default) != exprSQL(c.Default) {
+				if c.Default != nil {
+					alterations = append(alterations, spansql.SetDefault{Default: c.Default})
+				} else {
+					alterations = append(alterations, spansql.DropDefault{})
+				}
+			}
+
+			if allowCommitTimestamp(hc) != allowCommitTimestamp(c) {
+				alterations = append(alterations, spansql.SetColumnOptions{
+					Options: spansql.ColumnOptions{AllowCommitTimestamp: Ref(allowCommitTimestamp(c))},
+				})
+			}
+
+			for _, alteration := range alterations {
+				alterTables = append(alterTables, spansql.AlterTable{
+					Name:       name,
+					Alteration: spansql.AlterColumn{Name: c.Name, Alteration: alteration},
+				}.SQL())
+			}
+		}
+
+		// constraints
+		haveConstraints := map[string]spansql.TableConstraint{}
+		for _, c := range have.Constraints {
+			haveConstraints[constraintKey(c)] = c
+		}
+
+		wantConstraints := map[string]spansql.TableConstraint{}
+		for _, c := range want.Constraints {
+			wantConstraints[constraintKey(c)] = c
+		}
+
+		for _, key := range sortedKeys(haveConstraints) {
+			c := haveConstraints[key]
+
+			wc, found := wantConstraints[key]
+			if found && wc.SQL() == c.SQL() {
+				continue
+			}
+
+			if c.Name == "" {
+				return nil, fmt.Errorf("can't drop unnamed constraint on table %s: %s", name, c.SQL())
+			}
+
+			dropConstraints = append(dropConstraints, spansql.AlterTable{
+				Name:       name,
+				Alteration: spansql.DropConstraint{Name: c.Name},
+			}.SQL())
+		}
+
+		for _, key := range sortedKeys(wantConstraints) {
+			c := wantConstraints[key]
+
+			hc, found := haveConstraints[key]
+			if found && hc.SQL() == c.SQL() {
+				continue
+			}
+
+			addConstraints = append(addConstraints, spansql.AlterTable{
+				Name:       name,
+				Alteration: spansql.AddConstraint{Constraint: c},
+			}.SQL())
+		}
+
+		// row deletion policy
+		switch {
+		case have.RowDeletionPolicy == nil && want.RowDeletionPolicy != nil:
+			alterTables = append(alterTables, spansql.AlterTable{
+				Name:       name,
+				Alteration: spansql.AddRowDeletionPolicy{RowDeletionPolicy: *want.RowDeletionPolicy},
+			}.SQL())
+		case have.RowDeletionPolicy != nil && want.RowDeletionPolicy == nil:
+			alterTables = append(alterTables, spansql.AlterTable{
+				Name:       name,
+				Alteration: spansql.DropRowDeletionPolicy{},
+			}.SQL())
+		case have.RowDeletionPolicy != nil && *have.RowDeletionPolicy != *want.RowDeletionPolicy:
+			alterTables = append(alterTables, spansql.AlterTable{
+				Name:       name,
+				Alteration: spansql.ReplaceRowDeletionPolicy{RowDeletionPolicy: *want.RowDeletionPolicy},
+			}.SQL())
+		}
+	}
+
+	createTables = sortDDL(newTables)
+
+	// indexes, which are recreated when a column they use is dropped, as
+	// Spanner doesn't drop columns used by an index
+	for _, name := range sortedKeys(current.indexes) {
+		have := current.indexes[name]
+
+		want, found := desired.indexes[name]
+		if !found || want.SQL() != have.SQL() || usesColumn(have, droppedColumns[have.Table]) {
+			dropIndexes = append(dropIndexes, spansql.DropIndex{Name: name}.SQL())
+		}
+	}
+
+	for _, name := range sortedKeys(desired.indexes) {
+		want := desired.indexes[name]
+
+		have, found := current.indexes[name]
+		if !found || have.SQL() != want.SQL() || usesColumn(have, droppedColumns[have.Table]) {
+			createIndexes = append(createIndexes, want.SQL())
+		}
+	}
+
+	statements := slices.Concat(
+		dropStreams,
+		dropViews,
+		dropConstraints,
+		dropIndexes,
+		dropColumns,
+		dropTables,
+		createTables,
+		alterTables,
+		createIndexes,
+		addConstraints,
+		createViews,
+		alterStreams,
+		createStreams,
+	)
+
+	// every drop loses data or objects other statements may rely on
+	var destructive []string
+
+	for _, sql := range statements {
+		if diffDrop.MatchString(sql) {
+			destructive = append(destructive, sql)
+		}
+	}
+
+	if len(destructive) > 0 && !allowDestructive {
+		return nil, fmt.Errorf("destructive changes not allowed: %s", strings.Join(destructive, "; "))
+	}
+
+	return statements, nil
+}
+
+func dropColumn(dropped map[spansql.ID]map[spansql.ID]bool, table, column spansql.ID) {
+	if dropped[table] == nil {
+		dropped[table] = map[spansql.ID]bool{}
+	}
+	dropped[table][column] = true
+}
+
+// usesColumn returns whether an index keys or stores any of the columns.
+func usesColumn(index *spansql.CreateIndex, columns map[spansql.ID]bool) bool {
+	for _, part := range index.Columns {
+		if columns[part.Column] {
+			return true
+		}
+	}
+
+	return slices.ContainsFunc(index.Storing, func(column spansql.ID) bool {
+		return columns[column]
+	})
+}
+
+func sortedKeys[K ~string, V any](m map[K]V) []K {
+	return slices.Sorted(maps.Keys(m))
+}
+
+func constraintKey(c spansql.TableConstraint) string {
+	if c.Name != "" {
+		return string(c.Name)
+	}
+	return c.SQL()
+}
+
+func keyPartsSQL(parts []spansql.KeyPart) string {
+	var s []string
+	for _, part := range parts {
+		s = append(s, part.SQL())
+	}
+	return strings.Join(s, ", ")
+}
+
+func interleaveSQL(interleave *spansql.Interleave) string {
+	if interleave == nil {
+		return ""
+	}
+	return interleave.Parent.SQL() + " " + interleave.OnDelete.SQL()
+}
+
+func allowCommitTimestamp(c spansql.ColumnDef) bool {
+	return c.Options.AllowCommitTimestamp != nil && *c.Options.AllowCommitTimestamp
+}
+
+func exprSQL(expr spansql.Expr) string {
+	if expr == nil {
+		return ""
+	}
+	return expr.SQL()
+}
+
+func watchSQL(cs *spansql.CreateChangeStream) string {
+	if cs.WatchAllTables {
+		return "ALL"
+	}
+
+	var s []string
+	for _, w := range cs.Watch {
+		s = append(s, w.SQL())
+	}
+	return strings.Join(s, ", ")
+}
+
+func firstLine(sql string) string {
+	sql = strings.TrimSpace(sql)
+	line, _, _ := strings.Cut(sql, "\n")
+	return line
+}
@@ -0,0 +1,186 @@
+package migrations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffSchema(t *testing.T) {
+	testCases := []struct {
+		Name             string
+		Current          []string
+		Desired          []string
+		AllowDestructive bool
+		Expected         []string
+		Error            string
+	}{
+		{
+			Name:     "Empty",
+			Expected: nil,
+		},
+		{
+			Name: "Unchanged",
+			Current: []string{
+				"CREATE TABLE a (id INT64 NOT NULL) PRIMARY KEY (id)",
+			},
+			Desired: []string{
+				"CREATE TABLE a (\n  id INT64 NOT NULL,\n) PRIMARY KEY(id)",
+			},
+			Expected: nil,
+		},
+		{
+			Name: "Create",
+			Desired: []string{
+				"CREATE INDEX idx_b ON b (a_id)",
+				"CREATE TABLE b (id INT64 NOT NULL, a_id INT64, CONSTRAINT fk_b_a FOREIGN KEY (a_id) REFERENCES a (id)) PRIMARY KEY (id)",
+				"CREATE TABLE a (id INT64 NOT NULL) PRIMARY KEY (id)",
+				"CREATE CHANGE STREAM everything FOR ALL",
+			},
+			Expected: []string{
+				"CREATE TABLE a (\n  id INT64 NOT NULL,\n) PRIMARY KEY(id)",
+				"CREATE TABLE b (\n  id INT64 NOT NULL,\n  a_id INT64,\n  CONSTRAINT fk_b_a FOREIGN KEY (a_id) REFERENCES a (id) ON DELETE NO ACTION,\n) PRIMARY KEY(id)",
+				"CREATE INDEX idx_b ON b(a_id)",
+				"CREATE CHANGE STREAM everything FOR ALL",
+			},
+		},
+		{
+			Name: "Alter",
+			Current: []string{
+				"CREATE TABLE a (id INT64 NOT NULL, name STRING(10), update_time TIMESTAMP) PRIMARY KEY (id)",
+				"CREATE INDEX idx_a_name ON a (name)",
+			},
+			Desired: []string{
+				"CREATE TABLE a (id INT64 NOT NULL, name STRING(MAX) NOT NULL, slug STRING(MAX), update_time TIMESTAMP OPTIONS (allow_commit_timestamp = true), CONSTRAINT ck_a_id CHECK (id > 0)) PRIMARY KEY (id)",
+				"CREATE UNIQUE INDEX idx_a_name ON a (name)",
+			},
+			AllowDestructive: true,
+			Expected: []string{
+				"DROP INDEX idx_a_name",
+				"ALTER TABLE a ALTER COLUMN name STRING(MAX) NOT NULL",
+				"ALTER TABLE a ADD COLUMN slug STRING(MAX)",
+				"ALTER TABLE a ALTER COLUMN update_time SET OPTIONS (allow_commit_timestamp = true)",
+				"CREATE UNIQUE INDEX idx_a_name ON a(name)",
+				"ALTER TABLE a ADD CONSTRAINT ck_a_id CHECK (id > 0)",
+			},
+		},
+		{
+			Name: "Destructive",
+			Current: []string{
+				"CREATE TABLE a (id INT64 NOT NULL, name STRING(MAX)) PRIMARY KEY (id)",
+				"CREATE TABLE b (id INT64 NOT NULL) PRIMARY KEY (id)",
+			},
+			Desired: []string{
+				"CREATE TABLE a (id INT64 NOT NULL) PRIMARY KEY (id)",
+			},
+			Error: "destructive changes not allowed: ALTER TABLE a DROP COLUMN name; DROP TABLE b",
+		},
+		{
+			Name: "Destructive drops",
+			Current: []string{
+				"CREATE TABLE a (id INT64 NOT NULL, name STRING(MAX)) PRIMARY KEY (id)",
+				"CREATE INDEX idx_a_name ON a (name)",
+				"CREATE VIEW v SQL SECURITY INVOKER AS SELECT a.id FROM a",
+				"CREATE CHANGE STREAM a_stream FOR a",
+			},
+			Desired: []string{
+				"CREATE TABLE a (id INT64 NOT NULL, name STRING(MAX)) PRIMARY KEY (id)",
+			},
+			Error: "destructive changes not allowed: DROP CHANGE STREAM a_stream; DROP VIEW v; DROP INDEX idx_a_name",
+		},
+		{
+			Name: "Recreate index",
+			Current: []string{
+				"CREATE TABLE a (id INT64 NOT NULL, name STRING(MAX), lower_name STRING(MAX) AS (LOWER(name)) STORED) PRIMARY KEY (id)",
+				"CREATE INDEX idx_a_lower_name ON a (lower_name)",
+			},
+			Desired: []string{
+				"CREATE TABLE a (id INT64 NOT NULL, name STRING(MAX), lower_name STRING(MAX) AS (LOWER(TRIM(name))) STORED) PRIMARY KEY (id)",
+				"CREATE INDEX idx_a_lower_name ON a (lower_name)",
+			},
+			AllowDestructive: true,
+			Expected: []string{
+				"DROP INDEX idx_a_lower_name",
+				"ALTER TABLE a DROP COLUMN lower_name",
+				"ALTER TABLE a ADD COLUMN lower_name STRING(MAX) AS (LOWER(TRIM(name))) STORED",
+				"CREATE INDEX idx_a_lower_name ON a(lower_name)",
+			},
+		},
+		{
+			Name: "Drop",
+			Current: []string{
+				"CREATE TABLE a (id INT64 NOT NULL, name STRING(MAX)) PRIMARY KEY (id)",
+				"CREATE TABLE b (id INT64 NOT NULL, c_id INT64 NOT NULL) PRIMARY KEY (id, c_id), INTERLEAVE IN PARENT a ON DELETE CASCADE",
+				"CREATE INDEX idx_a_name ON a (name)",
+				"CREATE TABLE c (id INT64 NOT NULL, a_id INT64, CONSTRAINT fk_c_a FOREIGN KEY (a_id) REFERENCES a (id)) PRIMARY KEY (id)",
+				"CREATE CHANGE STREAM a_stream FOR a",
+			},
+			Desired: []string{
+				"CREATE TABLE c (id INT64 NOT NULL, a_id INT64) PRIMARY KEY (id)",
+			},
+			AllowDestructive: true,
+			Expected: []string{
+				"DROP CHANGE STREAM a_stream",
+				"ALTER TABLE c DROP CONSTRAINT fk_c_a",
+				"DROP INDEX idx_a_name",
+				"DROP TABLE b",
+				"DROP TABLE a",
+			},
+		},
+		{
+			Name: "Unmodeled",
+			Current: []string{
+				"CREATE PROTO BUNDLE (test.v1.User)",
+				"CREATE SEQUENCE seq OPTIONS (sequence_kind = 'bit_reversed_positive')",
+				"CREATE TABLE a (id INT64 NOT NULL) PRIMARY KEY (id)",
+			},
+			Desired: []string{
+				"CREATE TABLE a (id INT64 NOT NULL, name STRING(MAX)) PRIMARY KEY (id)",
+				"CREATE SEQUENCE seq OPTIONS (sequence_kind = 'bit_reversed_positive')",
+				"CREATE PROTO BUNDLE (\n  test.v1.User\n)",
+			},
+			Expected: []string{
+				"ALTER TABLE a ADD COLUMN name STRING(MAX)",
+			},
+		},
+		{
+			Name: "Unmodeled change",
+			Current: []string{
+				"CREATE PROTO BUNDLE (test.v1.User)",
+			},
+			Desired: []string{
+				"CREATE PROTO BUNDLE (test.v1.User, test.v1.Group)",
+			},
+			Error: `failed to parse "CREATE PROTO BUNDLE (test.v1.User)": -:1.0: unknown DDL statement`,
+		},
+		{
+			Name: "Primary key",
+			Current: []string{
+				"CREATE TABLE a (id INT64 NOT NULL) PRIMARY KEY (id)",
+			},
+			Desired: []string{
+				"CREATE TABLE a (id INT64 NOT NULL) PRIMARY KEY (id DESC)",
+			},
+			Error: "changing the primary key of table a isn't supported",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			current, err := parseSchema(tc.Current)
+			require.NoError(t, err)
+
+			desired, err := parseSchema(tc.Desired)
+			require.NoError(t, err)
+
+			statements, err := diffSchema(current, desired, tc.AllowDestructive)
+			if tc.Error != "" {
+				require.EqualError(t, err, tc.Error)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.Expected, statements)
+		})
+	}
+}
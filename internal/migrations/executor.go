@@ -0,0 +1,612 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"cloud.google.com/go/spanner/admin/database/apiv1/databasepb"
+	"google.golang.org/api/iterator"
+
+	"github.com/silas/jimmy/internal/constants"
+)
+
+// Executor runs migrations against the database.
+type Executor interface {
+	// EnsureDatabase creates the instance and database when enabled.
+	EnsureDatabase(ctx context.Context) error
+
+	DDLExecutor
+	DMLExecutor
+	QueryExecutor
+	TrackingExecutor
+}
+
+// DDLExecutor applies and inspects schema changes.
+type DDLExecutor interface {
+	// UpdateDDL submits DDL statements and returns the operation name.
+	UpdateDDL(ctx context.Context, statements []string, protoDescriptors []byte) (string, error)
+
+	// WaitDDL waits for a DDL operation to complete.
+	WaitDDL(ctx context.Context, operation string) error
+
+	// GetDDLOperation returns the current state of a DDL operation.
+	GetDDLOperation(ctx context.Context, operation string) (*DDLOperation, error)
+
+	// RunningDDLOperations returns the DDL operations of the database that
+	// haven't finished.
+	RunningDDLOperations(ctx context.Context) ([]*DDLOperation, error)
+
+	// GetDDL returns the database schema and proto descriptors.
+	GetDDL(ctx context.Context) ([]string, []byte, error)
+
+	// DropDatabase deletes the database.
+	DropDatabase(ctx context.Context) error
+}
+
+// DMLExecutor changes data.
+type DMLExecutor interface {
+	// BatchUpdate runs DML statements in a single transaction and returns
+	// the affected row counts.
+	BatchUpdate(ctx context.Context, statements []spanner.Statement) ([]int64, error)
+
+	// PartitionedUpdate runs a partitioned DML statement and returns a lower
+	// bound of the affected row count.
+	PartitionedUpdate(ctx context.Context, statement spanner.Statement) (int64, error)
+
+	// Apply writes mutations in a single transaction.
+	Apply(ctx context.Context, mutations []*spanner.Mutation) error
+}
+
+// QueryExecutor runs read-only queries.
+type QueryExecutor interface {
+	// QueryBool runs a query returning a single boolean value, returning
+	// whether a row was found.
+	QueryBool(ctx context.Context, statement spanner.Statement) (spanner.NullBool, bool, error)
+
+	// ColumnTypes returns the Spanner types of a table's columns keyed by
+	// column name, or none if the table doesn't exist.
+	ColumnTypes(ctx context.Context, table string) (map[string]string, error)
+
+	// DatabaseOptions returns the database options keyed by lowercase name.
+	DatabaseOptions(ctx context.Context) (map[string]string, error)
+
+	// ReadTimestamp returns the current time according to the database.
+	ReadTimestamp(ctx context.Context) (time.Time, error)
+
+	// TableExists returns whether a table exists.
+	TableExists(ctx context.Context, table string) (bool, error)
+
+	// SeedChecksums returns the checksums of the written seed files keyed
+	// by path.
+	SeedChecksums(ctx context.Context, table string) (map[string]string, error)
+
+	// ImportVersions returns the versions in the version table of another
+	// migration tool, mapped to whether they're dirty.
+	ImportVersions(ctx context.Context, table string) (map[int64]bool, error)
+}
+
+// TrackingExecutor reads and writes the migration table.
+type TrackingExecutor interface {
+	// EnsureTable creates or updates the migration table.
+	EnsureTable(ctx context.Context, table string) error
+
+	// TrackedMigrations returns the rows of the migration table, or none if
+	// the table doesn't exist.
+	TrackedMigrations(ctx context.Context, table string) ([]*TrackedMigration, error)
+
+	// StartMigration inserts a migration into the migration table.
+	StartMigration(ctx context.Context, table string, id int) error
+
+	// UpdateMigration sets columns of a migration in the migration table.
+	UpdateMigration(ctx context.Context, table string, id int, values map[string]any) error
+}
+
+// DDLOperation is the state of a DDL operation.
+type DDLOperation struct {
+	// Name is the name of the long-running operation.
+	Name string
+
+	// Done is whether the operation finished.
+	Done bool
+
+	// Err is the error the operation failed with.
+	Err error
+
+	// Metadata contains the statements and their progress.
+	Metadata *databasepb.UpdateDatabaseDdlMetadata
+}
+
+// TrackedMigration is a row of the migration table.
+type TrackedMigration struct {
+	ID int
+
+	// Complete is whether the migration completed.
+	Complete bool
+
+	// Operation is the DDL operation the migration is waiting for.
+	Operation string
+}
+
+// SetExecutor replaces the Spanner executor, to wrap or fake it.
+func (ms *Migrations) SetExecutor(executor Executor) {
+	ms.exec = executor
+}
+
+func (ms *Migrations) executor() Executor {
+	if ms.exec == nil {
+		ms.exec = &spannerExecutor{ms: ms}
+	}
+
+	return ms.exec
+}
+
+type spannerExecutor struct {
+	ms *Migrations
+}
+
+func (e *spannerExecutor) EnsureDatabase(ctx context.Context) error {
+	err := e.ms.ensureInstance(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to ensure instance: %w", err)
+	}
+
+	err = e.ms.ensureDatabase(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to ensure database: %w", err)
+	}
+
+	return nil
+}
+
+func (e *spannerExecutor) EnsureTable(ctx context.Context, table string) error {
+	err := e.ms.ensureTableDDL(ctx, table, constants.CreateMigrationTable)
+	if err != nil {
+		return err
+	}
+
+	// tables created by older versions are missing columns
+	columns, err := e.ColumnTypes(ctx, table)
+	if err != nil {
+		return err
+	}
+
+	var statements []string
+
+	for _, column := range []struct {
+		name string
+		sql  string
+	}{
+		{"operation", constants.AddMigrationOperationColumn},
+		{"backup", constants.AddMigrationBackupColumn},
+		{"restore_time", constants.AddMigrationRestoreTimeColumn},
+	} {
+		if _, found := columns[column.name]; !found {
+			statements = append(statements, fmt.Sprintf(column.sql, table))
+		}
+	}
+
+	if len(statements) == 0 {
+		return nil
+	}
+
+	return e.ms.runDDL(ctx, statements...)
+}
+
+func (e *spannerExecutor) UpdateDDL(ctx context.Context, statements []string, protoDescriptors []byte) (string, error) {
+	dbAdmin, err := e.ms.DatabaseAdmin(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	op, err := dbAdmin.UpdateDatabaseDdl(ctx, &databasepb.UpdateDatabaseDdlRequest{
+		Database:         e.ms.DatabaseName(),
+		Statements:       statements,
+		ProtoDescriptors: protoDescriptors,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return op.Name(), nil
+}
+
+func (e *spannerExecutor) WaitDDL(ctx context.Context, operation string) error {
+	dbAdmin, err := e.ms.DatabaseAdmin(ctx)
+	if err != nil {
+		return err
+	}
+
+	return dbAdmin.UpdateDatabaseDdlOperation(operation).Wait(ctx)
+}
+
+func (e *spannerExecutor) GetDDLOperation(ctx context.Context, operation string) (*DDLOperation, error) {
+	dbAdmin, err := e.ms.DatabaseAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	op := dbAdmin.UpdateDatabaseDdlOperation(operation)
+
+	err = op.Poll(ctx)
+	if err != nil && !op.Done() {
+		return nil, err
+	}
+
+	metadata, _ := op.Metadata()
+
+	return &DDLOperation{
+		Name:     operation,
+		Done:     op.Done(),
+		Err:      err,
+		Metadata: metadata,
+	}, nil
+}
+
+func (e *spannerExecutor) RunningDDLOperations(ctx context.Context) ([]*DDLOperation, error) {
+	dbAdmin, err := e.ms.DatabaseAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	it := dbAdmin.ListDatabaseOperations(ctx, &databasepb.ListDatabaseOperationsRequest{
+		Parent: e.ms.InstanceName(),
+		Filter: fmt.Sprintf(
+			"(metadata.@type:type.googleapis.com/%s) AND (name:%s/operations/)",
+			(&databasepb.UpdateDatabaseDdlMetadata{}).ProtoReflect().Descriptor().FullName(),
+			e.ms.DatabaseName(),
+		),
+	})
+
+	var operations []*DDLOperation
+
+	for {
+		op, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			return operations, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		if op.GetDone() {
+			continue
+		}
+
+		metadata := &databasepb.UpdateDatabaseDdlMetadata{}
+
+		err = op.GetMetadata().UnmarshalTo(metadata)
+		if err != nil || metadata.GetDatabase() != e.ms.DatabaseName() {
+			continue
+		}
+
+		operations = append(operations, &DDLOperation{
+			Name:     op.GetName(),
+			Metadata: metadata,
+		})
+	}
+}
+
+func (e *spannerExecutor) DropDatabase(ctx context.Context) error {
+	dbAdmin, err := e.ms.DatabaseAdmin(ctx)
+	if err != nil {
+		return err
+	}
+
+	return dbAdmin.DropDatabase(ctx, &databasepb.DropDatabaseRequest{
+		Database: e.ms.DatabaseName(),
+	})
+}
+
+func (e *spannerExecutor) BatchUpdate(ctx context.Context, statements []spanner.Statement) ([]int64, error) {
+	db, err := e.ms.Database(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var rowCounts []int64
+
+	_, err = db.ReadWriteTransaction(ctx, func(ctx context.Context, tx *spanner.ReadWriteTransaction) error {
+		var err error
+		rowCounts, err = tx.BatchUpdate(ctx, statements)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rowCounts, nil
+}
+
+func (e *spannerExecutor) PartitionedUpdate(ctx context.Context, statement spanner.Statement) (int64, error) {
+	db, err := e.ms.Database(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return db.PartitionedUpdate(ctx, statement)
+}
+
+func (e *spannerExecutor) Apply(ctx context.Context, mutations []*spanner.Mutation) error {
+	db, err := e.ms.Database(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Apply(ctx, mutations)
+	return err
+}
+
+func (e *spannerExecutor) QueryBool(ctx context.Context, statement spanner.Statement) (spanner.NullBool, bool, error) {
+	db, err := e.ms.Database(ctx)
+	if err != nil {
+		return spanner.NullBool{}, false, err
+	}
+
+	return readBool(db.Single().Query(ctx, statement))
+}
+
+func (e *spannerExecutor) ColumnTypes(ctx context.Context, table string) (map[string]string, error) {
+	db, err := e.ms.Database(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]string{}
+
+	err = db.Single().Query(ctx, spanner.Statement{
+		SQL: constants.SelectColumns,
+		Params: map[string]any{
+			"tableSchema": "",
+			"tableName":   table,
+		},
+	}).Do(func(r *spanner.Row) error {
+		var name, spannerType string
+
+		err := r.Columns(&name, &spannerType)
+		if err != nil {
+			return err
+		}
+
+		columns[name] = spannerType
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return columns, nil
+}
+
+func (e *spannerExecutor) DatabaseOptions(ctx context.Context) (map[string]string, error) {
+	db, err := e.ms.Database(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	options := map[string]string{}
+
+	err = db.Single().Query(ctx, spanner.Statement{
+		SQL: constants.SelectDatabaseOptions,
+	}).Do(func(r *spanner.Row) error {
+		var name string
+		var value spanner.NullString
+
+		err := r.Columns(&name, &value)
+		if err != nil {
+			return err
+		}
+
+		options[strings.ToLower(name)] = value.StringVal
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return options, nil
+}
+
+func (e *spannerExecutor) ReadTimestamp(ctx context.Context) (time.Time, error) {
+	db, err := e.ms.Database(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	tx := db.Single()
+	defer tx.Close()
+
+	err = tx.Query(ctx, spanner.Statement{SQL: constants.SelectOne}).Do(func(r *spanner.Row) error {
+		return nil
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return tx.Timestamp()
+}
+
+func (e *spannerExecutor) TableExists(ctx context.Context, table string) (bool, error) {
+	db, err := e.ms.Database(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var exists bool
+
+	err = db.Single().Query(ctx, spanner.Statement{
+		SQL: constants.SelectMigrationsTable,
+		Params: map[string]any{
+			"tableSchema": "",
+			"tableName":   table,
+		},
+	}).Do(func(r *spanner.Row) error {
+		exists = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (e *spannerExecutor) SeedChecksums(ctx context.Context, table string) (map[string]string, error) {
+	db, err := e.ms.Database(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	checksums := map[string]string{}
+
+	err = db.Single().Query(ctx, spanner.Statement{
+		SQL: fmt.Sprintf(constants.SelectSeeds, table),
+	}).Do(func(r *spanner.Row) error {
+		var path, checksum string
+
+		err := r.Columns(&path, &checksum)
+		if err != nil {
+			return err
+		}
+
+		checksums[path] = checksum
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return checksums, nil
+}
+
+func (e *spannerExecutor) ImportVersions(ctx context.Context, table string) (map[int64]bool, error) {
+	db, err := e.ms.Database(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := map[int64]bool{}
+
+	err = db.Single().Query(ctx, spanner.Statement{
+		SQL: fmt.Sprintf(constants.SelectImportVersion, table),
+	}).Do(func(r *spanner.Row) error {
+		var version int64
+		var dirty spanner.NullBool
+
+		err := r.Columns(&version, &dirty)
+		if err != nil {
+			return err
+		}
+
+		versions[version] = dirty.Valid && dirty.Bool
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+func (e *spannerExecutor) TrackedMigrations(ctx context.Context, table string) ([]*TrackedMigration, error) {
+	columns, err := e.ColumnTypes(ctx, table)
+	if err != nil || len(columns) == 0 {
+		return nil, err
+	}
+
+	// tables created by older versions are missing the column until the
+	// next upgrade adds it
+	operation := "operation"
+	if _, found := columns[operation]; !found {
+		operation = constants.SelectMigrationsNoOperation
+	}
+
+	db, err := e.ms.Database(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var tracked []*TrackedMigration
+
+	err = db.Single().Query(ctx, spanner.Statement{
+		SQL: fmt.Sprintf(constants.SelectMigrations, operation, table),
+	}).Do(func(r *spanner.Row) error {
+		var id int64
+		var complete bool
+		var operation spanner.NullString
+
+		err := r.Columns(&id, &complete, &operation)
+		if err != nil {
+			return err
+		}
+
+		tracked = append(tracked, &TrackedMigration{
+			ID:        int(id),
+			Complete:  complete,
+			Operation: operation.StringVal,
+		})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tracked, nil
+}
+
+func (e *spannerExecutor) StartMigration(ctx context.Context, table string, id int) error {
+	db, err := e.ms.Database(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Apply(ctx, []*spanner.Mutation{
+		spanner.Insert(
+			table,
+			[]string{"id", "start_time"},
+			[]any{int64(id), spanner.CommitTimestamp},
+		),
+	})
+	return err
+}
+
+func (e *spannerExecutor) UpdateMigration(ctx context.Context, table string, id int, values map[string]any) error {
+	db, err := e.ms.Database(ctx)
+	if err != nil {
+		return err
+	}
+
+	row := map[string]any{"id": int64(id)}
+
+	for column, value := range values {
+		row[column] = value
+	}
+
+	_, err = db.Apply(ctx, []*spanner.Mutation{
+		spanner.UpdateMap(table, row),
+	})
+	return err
+}
+
+func (e *spannerExecutor) GetDDL(ctx context.Context) ([]string, []byte, error) {
+	dbAdmin, err := e.ms.DatabaseAdmin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ddl, err := dbAdmin.GetDatabaseDdl(ctx, &databasepb.GetDatabaseDdlRequest{
+		Database: e.ms.DatabaseName(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ddl.Statements, ddl.ProtoDescriptors, nil
+}
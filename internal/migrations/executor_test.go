@@ -0,0 +1,221 @@
+package migrations_test
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/silas/jimmy/internal/migrations"
+	"github.com/silas/jimmy/internal/migrations/migrationstest"
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
+)
+
+func TestExecutor(t *testing.T) {
+	h := offlineHelper(t)
+	ctx := h.Ctx
+	ms := h.Migrations
+	ms.Config.Environments = []string{"staging"}
+
+	executor := migrationstest.NewExecutor()
+	ms.SetExecutor(executor)
+
+	require.NoError(t, ms.SetEnv("staging"))
+
+	createUsers := "CREATE TABLE users (id INT64 NOT NULL, name STRING(MAX)) PRIMARY KEY (id)"
+	createIndex := "CREATE INDEX users_by_name ON users (name)"
+	insertAlice := "INSERT INTO users (id, name) VALUES (1, 'alice')"
+	insertBob := "INSERT INTO users (id, name) VALUES (2, 'bob')"
+	insertStaging := "INSERT INTO users (id, name) VALUES (3, 'staging')"
+	backfill := "UPDATE users SET name = LOWER(name) WHERE name != LOWER(name) LIMIT @batch_size"
+	hasUsers := "SELECT COUNT(*) > 0 FROM users"
+	hasAdmins := "SELECT COUNT(*) > 0 FROM users WHERE name = 'admin'"
+
+	m, err := ms.Create(ctx, migrations.CreateInput{Name: "users", SQL: createUsers})
+	require.NoError(t, err)
+
+	for _, input := range []migrations.AddUpgradeInput{
+		{SQL: createIndex},
+		{SQL: insertAlice},
+		{SQL: insertBob},
+		{SQL: insertStaging, ExcludeEnvs: []string{"staging"}},
+		{SQL: hasUsers, Type: jimmyv1.Type_ASSERT},
+		{SQL: insertStaging, When: hasAdmins},
+		{
+			SQL:     backfill,
+			Type:    jimmyv1.Type_BATCHED_DML,
+			Batched: &jimmyv1.BatchedOptions{Pause: durationpb.New(0)},
+		},
+	} {
+		input.ID = m.ID()
+		require.NoError(t, ms.AddUpgrade(ctx, input))
+	}
+
+	executor.Results[hasUsers] = true
+	executor.RowCounts[backfill] = []int64{10, 5}
+
+	plan, err := ms.Plan(ctx)
+	require.NoError(t, err)
+	require.Len(t, plan.Migrations, 1)
+	require.Len(t, plan.Migrations[0].Statements, 8)
+	require.False(t, plan.Migrations[0].Statements[4].Enabled)
+	require.NotNil(t, plan.Migrations[0].Statements[6].When)
+	require.False(t, *plan.Migrations[0].Statements[6].When)
+
+	var progress []int64
+
+	err = ms.Upgrade(ctx, migrations.UpgradeOnProgress(
+		func(m *migrations.Migration, s *jimmyv1.Statement, iteration int, rowCount int64) {
+			progress = append(progress, rowCount)
+		},
+	))
+	require.NoError(t, err)
+
+	var calls []migrationstest.Call
+
+	for _, call := range executor.Calls() {
+		calls = append(calls, *call)
+	}
+
+	require.Equal(t, []migrationstest.Call{
+		{Method: "QueryBool", Statements: []string{hasAdmins}},
+		{Method: "UpdateDDL", Statements: []string{createUsers, createIndex}},
+		{Method: "BatchUpdate", Statements: []string{insertAlice, insertBob}},
+		{Method: "QueryBool", Statements: []string{hasUsers}},
+		{Method: "QueryBool", Statements: []string{hasAdmins}},
+		{Method: "BatchUpdate", Statements: []string{backfill}},
+		{Method: "BatchUpdate", Statements: []string{backfill}},
+		{Method: "BatchUpdate", Statements: []string{backfill}},
+	}, calls)
+	require.Equal(t, []int64{10, 5, 0}, progress)
+	require.Equal(t, []string{createUsers, createIndex}, executor.DDL())
+
+	tracked, err := executor.TrackedMigrations(ctx, ms.Config.Table)
+	require.NoError(t, err)
+	require.Equal(t, []*migrations.TrackedMigration{{ID: m.ID(), Complete: true}}, tracked)
+
+	status, err := ms.Status(ctx)
+	require.NoError(t, err)
+	require.Equal(t, m.ID(), status.CurrentID)
+	require.Empty(t, status.Pending)
+
+	// failed assertion stops the migration
+	{
+		m, err := ms.Create(ctx, migrations.CreateInput{
+			Name: "check",
+			SQL:  hasAdmins,
+			Type: jimmyv1.Type_ASSERT,
+		})
+		require.NoError(t, err)
+
+		executor.Results[hasAdmins] = false
+
+		err = ms.Upgrade(ctx)
+		require.ErrorIs(t, err, migrations.ErrAssertion)
+
+		applied, err := ms.AppliedIDs(ctx)
+		require.NoError(t, err)
+		require.NotContains(t, applied, m.ID())
+
+		// the migration runs once the assertion passes
+		executor.Results[hasAdmins] = true
+
+		require.NoError(t, ms.Upgrade(ctx))
+
+		applied, err = ms.AppliedIDs(ctx)
+		require.NoError(t, err)
+		require.True(t, applied[m.ID()])
+	}
+
+	// database options are compared with the executor's options
+	{
+		ms.Config.DatabaseOptions = &jimmyv1.DatabaseOptions{
+			DefaultTimeZone:  proto.String("UTC"),
+			OptimizerVersion: proto.Int64(6),
+		}
+
+		executor.Options["optimizer_version"] = "6"
+
+		drift, err := ms.DatabaseOptionsDrift(ctx)
+		require.NoError(t, err)
+		require.Len(t, drift, 1)
+		require.Equal(t, "default_time_zone: (unset) -> UTC", drift[0].String())
+	}
+}
+
+func TestExecutor_outOfOrder(t *testing.T) {
+	h := offlineHelper(t)
+	ctx := h.Ctx
+	ms := h.Migrations
+	ms.Config.IdScheme = jimmyv1.IdScheme_TIMESTAMP
+
+	executor := migrationstest.NewExecutor()
+	ms.SetExecutor(executor)
+
+	_, err := ms.Create(ctx, migrations.CreateInput{Name: "b", SQL: "CREATE TABLE b (id INT64) PRIMARY KEY (id)"})
+	require.NoError(t, err)
+	require.NoError(t, ms.Upgrade(ctx))
+
+	// a migration merged from another branch with an older timestamp
+	err = os.WriteFile(
+		path.Join(ms.Config.Path, "20200101000000_a.yaml"),
+		[]byte("upgrade:\n  - sql: CREATE TABLE a (id INT64) PRIMARY KEY (id)\n"),
+		0644,
+	)
+	require.NoError(t, err)
+
+	loaded := migrations.New(ms.Path)
+	loaded.SetExecutor(executor)
+	require.NoError(t, loaded.Load(ctx))
+	loaded.Config.IdScheme = jimmyv1.IdScheme_TIMESTAMP
+
+	err = loaded.Upgrade(ctx)
+	require.ErrorIs(t, err, migrations.ErrOutOfOrder)
+
+	require.NoError(t, loaded.Upgrade(ctx, migrations.UpgradeAllowOutOfOrder(true)))
+
+	applied, err := loaded.AppliedIDs(ctx)
+	require.NoError(t, err)
+	require.True(t, applied[20200101000000])
+}
+
+func TestExecutor_waitOperations(t *testing.T) {
+	h := offlineHelper(t)
+	ctx := h.Ctx
+	ms := h.Migrations
+
+	executor := migrationstest.NewExecutor()
+	ms.SetExecutor(executor)
+
+	m, err := ms.Create(ctx, migrations.CreateInput{Name: "a", SQL: "CREATE TABLE a (id INT64) PRIMARY KEY (id)"})
+	require.NoError(t, err)
+
+	var submitted string
+
+	require.NoError(t, ms.Upgrade(
+		ctx,
+		migrations.UpgradeNoWait(true),
+		migrations.UpgradeOnOperation(func(m *migrations.Migration, name string) {
+			submitted = name
+		}),
+	))
+	require.NotEmpty(t, submitted)
+
+	var completed []int
+
+	// the upgrade options apply to the resumed migration
+	operations, err := ms.WaitOperations(ctx, nil, nil, migrations.UpgradeOnComplete(func(m *migrations.Migration) {
+		completed = append(completed, m.ID())
+	}))
+	require.NoError(t, err)
+	require.Len(t, operations, 1)
+	require.Equal(t, submitted, operations[0].Name)
+	require.Equal(t, []int{m.ID()}, completed)
+
+	applied, err := ms.AppliedIDs(ctx)
+	require.NoError(t, err)
+	require.True(t, applied[m.ID()])
+}
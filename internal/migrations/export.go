@@ -0,0 +1,189 @@
+package migrations
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/silas/jimmy/internal/constants"
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
+)
+
+var ErrNoDescriptorPath = errors.New("descriptor path required")
+
+type ExportInput struct {
+	// From is the ID of the migration the database is at, the export
+	// starts after it.
+	From int
+
+	// To is the ID of the last exported migration, zero exports all.
+	To int
+
+	// Env is the built-in environment statements are filtered for,
+	// defaults to the detected environment.
+	Env jimmyv1.Environment
+
+	// DescriptorPath is the path prefix file descriptor sets are written
+	// to, as <prefix><id>_<name>.pb. Exporting statements with a file
+	// descriptor set fails without it.
+	DescriptorPath string
+}
+
+// ExportSQL writes the statements an upgrade from a migration would run as
+// a SQL script, returning the paths of the written file descriptor sets.
+func (ms *Migrations) ExportSQL(w io.Writer, input ExportInput) ([]string, error) {
+	env := input.Env
+	if env == jimmyv1.Environment_ALL {
+		env = ms.environment()
+	}
+
+	sequence, err := ms.sequence(input.From)
+	if err != nil {
+		return nil, err
+	}
+
+	var descriptorFiles []string
+
+	e := &exportWriter{w: w}
+
+	e.comment("%s export", constants.AppName)
+
+	for _, m := range sequence {
+		if input.To > 0 && m.ID() > input.To {
+			break
+		}
+
+		e.line("")
+		e.comment("migration %d: %s (%s)", m.ID(), m.Name(), m.FileName())
+
+		var batch *Batch
+
+		for pos, s := range m.data.GetUpgrade() {
+			enabled, err := ms.isEnabled(env, s)
+			if err != nil {
+				return nil, fmt.Errorf("migration %d upgrade[%d]: %w", m.ID(), pos, err)
+			}
+
+			if !enabled {
+				continue
+			}
+
+			if s.Type == jimmyv1.Type_AUTOMATIC {
+				s = proto.Clone(s).(*jimmyv1.Statement)
+				s.Type = detectType(s.Sql)
+			}
+
+			if batch == nil || s.When != nil || batch.flush(s) {
+				batch = &Batch{}
+
+				e.line("")
+				e.comment("batch: %s", exportBatchType(s))
+
+				if set := s.GetFileDescriptorSet(); set != "" {
+					if input.DescriptorPath == "" {
+						return nil, fmt.Errorf("%w: migration %d uses file descriptor set %q", ErrNoDescriptorPath, m.ID(), set)
+					}
+
+					path, err := ms.exportDescriptors(m, set, input.DescriptorPath)
+					if err != nil {
+						return nil, err
+					}
+
+					descriptorFiles = append(descriptorFiles, path)
+
+					e.comment("proto descriptors: %s", path)
+				}
+			} else {
+				e.line("")
+			}
+
+			batch.add(s)
+
+			if s.When != nil {
+				e.comment("when: %s", oneLine(s.GetWhen()))
+			}
+
+			if load := s.GetLoad(); load != nil {
+				e.comment("load %s into %s", load.GetFile(), load.GetTable())
+				continue
+			}
+
+			e.line(strings.TrimSpace(s.Sql) + ";")
+		}
+	}
+
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	return descriptorFiles, nil
+}
+
+// exportDescriptors writes a file descriptor set of a migration to a
+// sidecar file.
+func (ms *Migrations) exportDescriptors(m *Migration, set, prefix string) (string, error) {
+	fileDescriptorSet := m.data.GetFileDescriptorSets()[set]
+	if fileDescriptorSet == nil {
+		return "", fmt.Errorf("migration %d file descriptor set %q not found", m.ID(), set)
+	}
+
+	b, err := proto.Marshal(fileDescriptorSet)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal %q file descriptor set: %w", set, err)
+	}
+
+	path := fmt.Sprintf("%s%05d_%s%s", prefix, m.ID(), set, constants.SchemaProtoExt)
+
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return "", err
+	}
+
+	err = os.WriteFile(path, b, 0644)
+	if err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+func exportBatchType(s *jimmyv1.Statement) string {
+	switch s.Type {
+	case jimmyv1.Type_BATCHED_DML:
+		size := s.GetBatched().GetSize()
+		if size == 0 {
+			size = constants.BatchSize
+		}
+
+		return fmt.Sprintf("%s, repeated until no rows are affected with @%s = %d",
+			s.Type, constants.BatchSizeParam, size)
+	case jimmyv1.Type_ASSERT:
+		return fmt.Sprintf("%s, must return no rows or true", s.Type)
+	default:
+		return s.Type.String()
+	}
+}
+
+func oneLine(sql string) string {
+	return strings.Join(strings.Fields(sql), " ")
+}
+
+type exportWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (e *exportWriter) line(s string) {
+	if e.err == nil {
+		_, e.err = fmt.Fprintln(e.w, s)
+	}
+}
+
+func (e *exportWriter) comment(format string, a ...any) {
+	e.line("-- " + fmt.Sprintf(format, a...))
+}
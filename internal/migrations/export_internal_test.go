@@ -0,0 +1,134 @@
+package migrations
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/silas/jimmy/internal/constants"
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
+)
+
+func TestExportSQL(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	ms := New("")
+	ms.emulator = false
+	ms.Config.Path = tmpDir
+
+	fileDescriptorSet := &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{{Name: proto.String("test.proto")}},
+	}
+
+	for _, m := range []*Migration{
+		newMigration(ms, 1, "00001_users.yaml", &jimmyv1.Migration{
+			Upgrade: []*jimmyv1.Statement{
+				{Sql: "CREATE TABLE users (id INT64 NOT NULL) PRIMARY KEY (id)\n"},
+				{Sql: "INSERT INTO users (id) VALUES (1)\n", Type: jimmyv1.Type_DML},
+			},
+		}),
+		newMigration(ms, 2, "00002_emulator.yaml", &jimmyv1.Migration{
+			Upgrade: []*jimmyv1.Statement{
+				{Sql: "INSERT INTO users (id) VALUES (2)\n", Env: jimmyv1.Environment_EMULATOR},
+				{Sql: "INSERT INTO users (id) VALUES (3)\n", Env: jimmyv1.Environment_GOOGLE_CLOUD},
+			},
+		}),
+		newMigration(ms, 3, "00003_proto.yaml", &jimmyv1.Migration{
+			Upgrade: []*jimmyv1.Statement{
+				{
+					Sql:               "CREATE PROTO BUNDLE (test.Test)\n",
+					Type:              jimmyv1.Type_DDL,
+					FileDescriptorSet: Ref(constants.UpgradeFileDescriptorSet),
+				},
+				{
+					Sql:  "SELECT COUNT(*) > 0 FROM users\n",
+					Type: jimmyv1.Type_ASSERT,
+					When: Ref("SELECT true\nFROM users"),
+				},
+			},
+			FileDescriptorSets: map[string]*descriptorpb.FileDescriptorSet{
+				constants.UpgradeFileDescriptorSet: fileDescriptorSet,
+			},
+		}),
+		newMigration(ms, 4, "00004_squash.yaml", &jimmyv1.Migration{
+			SquashId: Ref[int64](1),
+			Upgrade: []*jimmyv1.Statement{
+				{Sql: "CREATE TABLE users (id INT64 NOT NULL) PRIMARY KEY (id)\n"},
+			},
+		}),
+	} {
+		ms.setMigration(m)
+	}
+
+	var b strings.Builder
+
+	files, err := ms.ExportSQL(&b, ExportInput{
+		Env:            jimmyv1.Environment_GOOGLE_CLOUD,
+		DescriptorPath: filepath.Join(tmpDir, "out", "export_"),
+	})
+	require.NoError(t, err)
+	require.Empty(t, files)
+	require.Equal(t, `-- jimmy export
+
+-- migration 4: squash (00004_squash.yaml)
+
+-- batch: DDL
+CREATE TABLE users (id INT64 NOT NULL) PRIMARY KEY (id);
+`, b.String())
+
+	// proto descriptors aren't written without a path
+	_, err = ms.ExportSQL(&b, ExportInput{From: 1, To: 3})
+	require.ErrorIs(t, err, ErrNoDescriptorPath)
+	require.EqualError(t, err, `descriptor path required: migration 3 uses file descriptor set "upgrade"`)
+
+	b.Reset()
+
+	files, err = ms.ExportSQL(&b, ExportInput{
+		From:           1,
+		To:             3,
+		Env:            jimmyv1.Environment_GOOGLE_CLOUD,
+		DescriptorPath: filepath.Join(tmpDir, "out", "export_"),
+	})
+	require.NoError(t, err)
+
+	path := filepath.Join(tmpDir, "out", "export_00003_upgrade.pb")
+	require.Equal(t, []string{path}, files)
+	require.Equal(t, `-- jimmy export
+
+-- migration 2: emulator (00002_emulator.yaml)
+
+-- batch: DML
+INSERT INTO users (id) VALUES (3);
+
+-- migration 3: proto (00003_proto.yaml)
+
+-- batch: DDL
+-- proto descriptors: `+path+`
+CREATE PROTO BUNDLE (test.Test);
+
+-- batch: ASSERT, must return no rows or true
+-- when: SELECT true FROM users
+SELECT COUNT(*) > 0 FROM users;
+`, b.String())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	written := &descriptorpb.FileDescriptorSet{}
+	require.NoError(t, proto.Unmarshal(data, written))
+	require.True(t, proto.Equal(fileDescriptorSet, written))
+
+	// the environment is filtered without changing the detected one
+	b.Reset()
+
+	_, err = ms.ExportSQL(&b, ExportInput{From: 1, To: 2, Env: jimmyv1.Environment_EMULATOR})
+	require.NoError(t, err)
+	require.Contains(t, b.String(), "INSERT INTO users (id) VALUES (2);")
+	require.NotContains(t, b.String(), "INSERT INTO users (id) VALUES (3);")
+	require.Equal(t, jimmyv1.Environment_GOOGLE_CLOUD, ms.environment())
+}
@@ -0,0 +1,252 @@
+package migrations
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/silas/jimmy/internal/constants"
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
+)
+
+var importFilePatterns = map[string]*regexp.Regexp{
+	constants.ImportGolangMigrate: regexp.MustCompile(`^([0-9]+)_(.*)\.up\.sql$`),
+	constants.ImportWrench:        regexp.MustCompile(`^([0-9]+)(?:_([^.]*))?(?:\.up)?\.sql$`),
+}
+
+type ImportInput struct {
+	// From is the tool the migrations were written for.
+	From string
+
+	// Path is the directory containing the migration files.
+	Path string
+
+	// MarkApplied sets whether migrations recorded in the version table of
+	// the tool are added to the migration table, so they aren't run again.
+	MarkApplied bool
+
+	// VersionTable is the version table of the tool, defaults to
+	// SchemaMigrations.
+	VersionTable string
+}
+
+type ImportedMigration struct {
+	// Migration is the created migration, nil when the file didn't contain
+	// any statements.
+	Migration *Migration
+
+	// FileName is the name of the imported file.
+	FileName string
+
+	// Version is the version of the imported file.
+	Version int64
+
+	// Applied is whether the migration was marked as applied.
+	Applied bool
+}
+
+type importFile struct {
+	fileName string
+	version  int64
+	name     string
+}
+
+// Import creates migrations from the migration files of another tool.
+func (ms *Migrations) Import(ctx context.Context, input ImportInput) ([]*ImportedMigration, error) {
+	// changelogs can contain preconditions, rollbacks and non-SQL change types
+	if input.From == constants.ImportLiquibase {
+		return nil, fmt.Errorf(
+			"%w: liquibase changelogs can't be imported, generate SQL with \"liquibase update-sql\" and add it to a migration",
+			errors.ErrUnsupported,
+		)
+	}
+
+	pattern, found := importFilePatterns[input.From]
+	if !found {
+		return nil, fmt.Errorf("unsupported import format %q", input.From)
+	}
+
+	files, err := importFiles(input.Path, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no %s migrations found in %q", input.From, input.Path)
+	}
+
+	err = ms.ensureEnv(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var appliedVersion int64 = -1
+
+	if input.MarkApplied {
+		appliedVersion, err = ms.importVersion(ctx, cmp.Or(input.VersionTable, constants.ImportVersionTable))
+		if err != nil {
+			return nil, err
+		}
+
+		err = ms.ensureTable(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to ensure migration table: %w", err)
+		}
+	}
+
+	var imported []*ImportedMigration
+
+	for _, file := range files {
+		b, err := os.ReadFile(filepath.Join(input.Path, file.fileName))
+		if err != nil {
+			return nil, err
+		}
+
+		im := &ImportedMigration{
+			FileName: file.fileName,
+			Version:  file.version,
+		}
+
+		imported = append(imported, im)
+
+		data := &jimmyv1.Migration{}
+
+		for _, sql := range splitStatements(string(b)) {
+			statementType := detectType(sql)
+
+			// other tools run updates and deletes in a transaction
+			if statementType == jimmyv1.Type_PARTITIONED_DML {
+				statementType = jimmyv1.Type_DML
+			}
+
+			statement, err := ms.newStatement(sql, jimmyv1.Environment_ALL, "", statementType)
+			if err != nil {
+				return nil, err
+			}
+
+			data.Upgrade = append(data.Upgrade, statement)
+		}
+
+		if len(data.Upgrade) == 0 {
+			continue
+		}
+
+		slug := Slugify(file.name)
+		if slug == "" {
+			slug = "none"
+		}
+
+		im.Migration, err = ms.create(slug, data)
+		if err != nil {
+			return nil, err
+		}
+
+		if file.version <= appliedVersion {
+			err = ms.markApplied(ctx, im.Migration.ID())
+			if err != nil {
+				return nil, err
+			}
+
+			im.Applied = true
+		}
+	}
+
+	return imported, nil
+}
+
+// importFiles returns the migration files matching the pattern, ordered
+// by version.
+func importFiles(path string, pattern *regexp.Regexp) ([]*importFile, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*importFile
+
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		match := pattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version in %q: %w", entry.Name(), err)
+		}
+
+		files = append(files, &importFile{
+			fileName: entry.Name(),
+			version:  version,
+			name:     match[2],
+		})
+	}
+
+	slices.SortFunc(files, func(a, b *importFile) int {
+		return cmp.Compare(a.version, b.version)
+	})
+
+	for i := 1; i < len(files); i++ {
+		if files[i].version == files[i-1].version {
+			return nil, fmt.Errorf("%q and %q have the same version", files[i-1].fileName, files[i].fileName)
+		}
+	}
+
+	return files, nil
+}
+
+// importVersion returns the version recorded in the version table of the
+// tool, or -1 if there isn't one.
+func (ms *Migrations) importVersion(ctx context.Context, table string) (int64, error) {
+	err := ms.ensureAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	exists, err := ms.executor().TableExists(ctx, table)
+	if err != nil {
+		return 0, err
+	}
+
+	if !exists {
+		return 0, fmt.Errorf("%q version table not found", table)
+	}
+
+	versions, err := ms.executor().ImportVersions(ctx, table)
+	if err != nil {
+		return 0, err
+	}
+
+	var version int64 = -1
+
+	for _, rowVersion := range slices.Sorted(maps.Keys(versions)) {
+		if versions[rowVersion] {
+			return 0, fmt.Errorf("version %d in %q is dirty, fix it before importing", rowVersion, table)
+		}
+
+		version = rowVersion
+	}
+
+	return version, nil
+}
+
+// markApplied records a migration as completed without running it.
+func (ms *Migrations) markApplied(ctx context.Context, id int) error {
+	err := ms.startMigration(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return ms.completeMigration(ctx, id)
+}
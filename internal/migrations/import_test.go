@@ -0,0 +1,163 @@
+package migrations_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/silas/jimmy/internal/constants"
+	"github.com/silas/jimmy/internal/migrations"
+	"github.com/silas/jimmy/internal/migrations/migrationstest"
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
+)
+
+func TestImport(t *testing.T) {
+	ctx := context.Background()
+
+	for _, test := range []struct {
+		from  string
+		files map[string]string
+	}{
+		{
+			from: constants.ImportWrench,
+			files: map[string]string{
+				"000001_create_users.sql":  "CREATE TABLE users (\n  id INT64 NOT NULL\n) PRIMARY KEY (id);\nCREATE INDEX users_by_id ON users (id);\n",
+				"000002.sql":               "-- nothing to do\n",
+				"000010_add_users.sql":     "INSERT INTO users (id) VALUES (1);\nINSERT INTO users (id) VALUES (2);",
+				"000003_backfill.sql":      "UPDATE users SET id = id WHERE true",
+				"000003_backfill.down.sql": "UPDATE users SET id = id WHERE false",
+				"README.md":                "# migrations",
+			},
+		},
+		{
+			from: constants.ImportGolangMigrate,
+			files: map[string]string{
+				"1_create_users.up.sql":   "CREATE TABLE users (\n  id INT64 NOT NULL\n) PRIMARY KEY (id);\nCREATE INDEX users_by_id ON users (id)",
+				"1_create_users.down.sql": "DROP TABLE users",
+				"2_nothing.up.sql":        "",
+				"10_add_users.up.sql":     "INSERT INTO users (id) VALUES (1);\nINSERT INTO users (id) VALUES (2);",
+				"3_backfill.up.sql":       "UPDATE users SET id = id WHERE true;",
+			},
+		},
+	} {
+		t.Run(test.from, func(t *testing.T) {
+			h := offlineHelper(t)
+			ms := h.Migrations
+
+			importPath := path.Join(h.Path, "import")
+			require.NoError(t, os.Mkdir(importPath, 0755))
+
+			for name, content := range test.files {
+				require.NoError(t, os.WriteFile(path.Join(importPath, name), []byte(content), 0644))
+			}
+
+			imported, err := ms.Import(ctx, migrations.ImportInput{
+				From: test.from,
+				Path: importPath,
+			})
+			require.NoError(t, err)
+			require.Len(t, imported, 4)
+
+			require.Equal(t, []int64{1, 2, 3, 10}, []int64{
+				imported[0].Version,
+				imported[1].Version,
+				imported[2].Version,
+				imported[3].Version,
+			})
+			require.Nil(t, imported[1].Migration)
+
+			require.Equal(t, "00001_create_users.yaml", imported[0].Migration.FileName())
+			require.Equal(t, "00002_backfill.yaml", imported[2].Migration.FileName())
+			require.Equal(t, "00003_add_users.yaml", imported[3].Migration.FileName())
+
+			for _, im := range imported {
+				require.False(t, im.Applied)
+			}
+
+			loaded := migrations.New(ms.Path)
+			require.NoError(t, loaded.Load(ctx))
+			require.NoError(t, loaded.Validate())
+
+			for id, expected := range map[int][]jimmyv1.Type{
+				1: {jimmyv1.Type_DDL, jimmyv1.Type_DDL},
+				2: {jimmyv1.Type_DML},
+				3: {jimmyv1.Type_DML, jimmyv1.Type_DML},
+			} {
+				m, err := loaded.Get(id)
+				require.NoError(t, err)
+
+				var types []jimmyv1.Type
+
+				for s := range m.Upgrade() {
+					types = append(types, s.Type)
+				}
+
+				require.Equal(t, expected, types)
+			}
+		})
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		ms := migrations.New(path.Join(t.TempDir(), constants.ConfigFile))
+
+		_, err := ms.Import(ctx, migrations.ImportInput{From: "flyway", Path: t.TempDir()})
+		require.EqualError(t, err, `unsupported import format "flyway"`)
+
+		_, err = ms.Import(ctx, migrations.ImportInput{From: constants.ImportLiquibase, Path: t.TempDir()})
+		require.ErrorIs(t, err, errors.ErrUnsupported)
+	})
+}
+
+func TestImport_markApplied(t *testing.T) {
+	h := offlineHelper(t)
+	ctx := h.Ctx
+	ms := h.Migrations
+
+	executor := migrationstest.NewExecutor()
+	ms.SetExecutor(executor)
+
+	importPath := path.Join(h.Path, "import")
+	require.NoError(t, os.Mkdir(importPath, 0755))
+
+	for name, content := range map[string]string{
+		"1_create_users.up.sql": "CREATE TABLE users (\n  id INT64 NOT NULL\n) PRIMARY KEY (id)",
+		"2_add_users.up.sql":    "INSERT INTO users (id) VALUES (1)",
+		"3_backfill.up.sql":     "UPDATE users SET id = id WHERE true",
+	} {
+		require.NoError(t, os.WriteFile(path.Join(importPath, name), []byte(content), 0644))
+	}
+
+	input := migrations.ImportInput{
+		From:        constants.ImportGolangMigrate,
+		Path:        importPath,
+		MarkApplied: true,
+	}
+
+	_, err := ms.Import(ctx, input)
+	require.EqualError(t, err, `"SchemaMigrations" version table not found`)
+
+	executor.Versions[constants.ImportVersionTable] = map[int64]bool{2: true}
+
+	_, err = ms.Import(ctx, input)
+	require.EqualError(t, err, `version 2 in "SchemaMigrations" is dirty, fix it before importing`)
+
+	executor.Versions[constants.ImportVersionTable] = map[int64]bool{1: false, 2: false}
+
+	imported, err := ms.Import(ctx, input)
+	require.NoError(t, err)
+	require.Len(t, imported, 3)
+	require.True(t, imported[0].Applied)
+	require.True(t, imported[1].Applied)
+	require.False(t, imported[2].Applied)
+
+	tracked, err := executor.TrackedMigrations(ctx, ms.Config.Table)
+	require.NoError(t, err)
+	require.Equal(t, []*migrations.TrackedMigration{
+		{ID: imported[0].Migration.ID(), Complete: true},
+		{ID: imported[1].Migration.ID(), Complete: true},
+	}, tracked)
+}
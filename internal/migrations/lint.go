@@ -0,0 +1,251 @@
+package migrations
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+type LintProblem struct {
+	Migration *Migration
+	Type      string
+	Message   string
+}
+
+func (p *LintProblem) String() string {
+	return fmt.Sprintf("%s: %s: %s", p.Migration.FileName(), p.Type, p.Message)
+}
+
+type protoDescriptor struct {
+	message *descriptorpb.DescriptorProto
+	enum    *descriptorpb.EnumDescriptorProto
+}
+
+// LintProtos compares the file descriptor sets of each migration with the
+// proto bundle in effect before it, returning changes that break readers of
+// existing data.
+func (ms *Migrations) LintProtos() ([]*LintProblem, error) {
+	sequence, err := ms.sequence(0)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []*LintProblem
+
+	bundle := map[string]*protoDescriptor{}
+	columnTypes := map[string]bool{}
+
+	for _, m := range sequence {
+		for _, s := range m.data.GetUpgrade() {
+			if !isProtoDDL(s.Sql) && !dmlDropProtoBundle.MatchString(s.Sql) {
+				addColumnTypes(columnTypes, s.Sql)
+			}
+		}
+
+		usedEnums := protoUsedEnums(bundle, columnTypes)
+
+		for _, name := range slices.Sorted(maps.Keys(m.data.GetFileDescriptorSets())) {
+			descriptors := protoDescriptors(m.data.GetFileDescriptorSets()[name])
+
+			for _, typeName := range slices.Sorted(maps.Keys(descriptors)) {
+				previous := bundle[typeName]
+				if previous == nil {
+					continue
+				}
+
+				for _, message := range compareProtoDescriptors(previous, descriptors[typeName], usedEnums[typeName]) {
+					problems = append(problems, &LintProblem{
+						Migration: m,
+						Type:      typeName,
+						Message:   message,
+					})
+				}
+			}
+		}
+
+		for _, s := range m.data.GetUpgrade() {
+			if dmlDropProtoBundle.MatchString(s.Sql) {
+				clear(bundle)
+				continue
+			}
+
+			if !isProtoDDL(s.Sql) {
+				continue
+			}
+
+			if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(s.Sql)), "CREATE") {
+				clear(bundle)
+			}
+
+			descriptors := protoDescriptors(m.data.GetFileDescriptorSets()[s.GetFileDescriptorSet()])
+
+			types, deleted := protoBundleTypes(s.Sql)
+
+			for _, t := range types {
+				if d := descriptors[t]; d != nil {
+					bundle[t] = d
+				}
+			}
+
+			for _, t := range deleted {
+				delete(bundle, t)
+			}
+		}
+	}
+
+	return problems, nil
+}
+
+// protoDescriptors indexes the messages and enums in a file descriptor set
+// by their fully-qualified name.
+func protoDescriptors(fileDescriptorSet *descriptorpb.FileDescriptorSet) map[string]*protoDescriptor {
+	descriptors := map[string]*protoDescriptor{}
+
+	if fileDescriptorSet == nil {
+		return descriptors
+	}
+
+	var addMessages func(prefix string, messages []*descriptorpb.DescriptorProto)
+	addMessages = func(prefix string, messages []*descriptorpb.DescriptorProto) {
+		for _, message := range messages {
+			name := prefix + message.GetName()
+			descriptors[name] = &protoDescriptor{message: message}
+
+			for _, enum := range message.EnumType {
+				descriptors[name+"."+enum.GetName()] = &protoDescriptor{enum: enum}
+			}
+
+			addMessages(name+".", message.NestedType)
+		}
+	}
+
+	for _, file := range fileDescriptorSet.File {
+		prefix := file.GetPackage()
+		if prefix != "" {
+			prefix += "."
+		}
+
+		for _, enum := range file.EnumType {
+			descriptors[prefix+enum.GetName()] = &protoDescriptor{enum: enum}
+		}
+
+		addMessages(prefix, file.MessageType)
+	}
+
+	return descriptors
+}
+
+var ddlColumnType = regexp.MustCompile(`([A-Za-z_]\w*(?:\.[A-Za-z_]\w*)+)`)
+
+// addColumnTypes adds the qualified type names referenced by a table
+// statement, which are the proto types that can be used by columns.
+func addColumnTypes(columnTypes map[string]bool, sql string) {
+	if !ddlCreate.MatchString(sql) && !ddlAlterTable.MatchString(sql) {
+		return
+	}
+
+	for _, match := range ddlColumnType.FindAllStringSubmatch(sql, -1) {
+		columnTypes[match[1]] = true
+	}
+}
+
+// protoUsedEnums returns the enums in the bundle used by columns, either
+// directly or through the fields of messages used by columns.
+func protoUsedEnums(bundle map[string]*protoDescriptor, columnTypes map[string]bool) map[string]bool {
+	used := map[string]bool{}
+	visited := map[string]bool{}
+
+	var visit func(name string)
+	visit = func(name string) {
+		if visited[name] {
+			return
+		}
+		visited[name] = true
+
+		d := bundle[name]
+		if d == nil {
+			return
+		}
+
+		if d.enum != nil {
+			used[name] = true
+			return
+		}
+
+		for _, field := range d.message.Field {
+			if field.GetTypeName() != "" {
+				visit(strings.TrimPrefix(field.GetTypeName(), "."))
+			}
+		}
+	}
+
+	for name := range columnTypes {
+		visit(name)
+	}
+
+	return used
+}
+
+func compareProtoDescriptors(previous, current *protoDescriptor, enumUsed bool) []string {
+	var messages []string
+
+	switch {
+	case previous.message != nil && current.message == nil:
+		return []string{"message changed to enum"}
+	case previous.enum != nil && current.enum == nil:
+		return []string{"enum changed to message"}
+	case previous.message != nil:
+		for _, field := range previous.message.Field {
+			i := slices.IndexFunc(current.message.Field, func(f *descriptorpb.FieldDescriptorProto) bool {
+				return f.GetNumber() == field.GetNumber()
+			})
+			if i == -1 {
+				j := slices.IndexFunc(current.message.Field, func(f *descriptorpb.FieldDescriptorProto) bool {
+					return f.GetName() == field.GetName()
+				})
+				if j >= 0 {
+					messages = append(messages, fmt.Sprintf("field %q renumbered from %d to %d",
+						field.GetName(), field.GetNumber(), current.message.Field[j].GetNumber()))
+				} else {
+					messages = append(messages, fmt.Sprintf("field %q (%d) removed",
+						field.GetName(), field.GetNumber()))
+				}
+				continue
+			}
+
+			if t, ct := fieldType(field), fieldType(current.message.Field[i]); t != ct {
+				messages = append(messages, fmt.Sprintf("field %q (%d) type changed from %s to %s",
+					field.GetName(), field.GetNumber(), t, ct))
+			}
+		}
+	case enumUsed:
+		for _, value := range previous.enum.Value {
+			if !slices.ContainsFunc(current.enum.Value, func(v *descriptorpb.EnumValueDescriptorProto) bool {
+				return v.GetNumber() == value.GetNumber()
+			}) {
+				messages = append(messages, fmt.Sprintf("enum value %s (%d) removed",
+					value.GetName(), value.GetNumber()))
+			}
+		}
+	}
+
+	return messages
+}
+
+func fieldType(field *descriptorpb.FieldDescriptorProto) string {
+	t := strings.ToLower(strings.TrimPrefix(field.GetType().String(), "TYPE_"))
+
+	if field.GetTypeName() != "" {
+		t = strings.TrimPrefix(field.GetTypeName(), ".")
+	}
+
+	if field.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED {
+		t = "repeated " + t
+	}
+
+	return t
+}
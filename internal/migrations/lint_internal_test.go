@@ -0,0 +1,106 @@
+package migrations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
+)
+
+func TestLintProtos(t *testing.T) {
+	field := func(name string, number int32, t descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:   Ref(name),
+			Number: Ref(number),
+			Type:   t.Enum(),
+			Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = Ref(typeName)
+		}
+		return f
+	}
+
+	value := func(name string, number int32) *descriptorpb.EnumValueDescriptorProto {
+		return &descriptorpb.EnumValueDescriptorProto{Name: Ref(name), Number: Ref(number)}
+	}
+
+	fileDescriptorSet := func(user []*descriptorpb.FieldDescriptorProto, status, kind []*descriptorpb.EnumValueDescriptorProto) *descriptorpb.FileDescriptorSet {
+		return &descriptorpb.FileDescriptorSet{
+			File: []*descriptorpb.FileDescriptorProto{{
+				Name:    Ref("test/v1/test.proto"),
+				Package: Ref("test.v1"),
+				MessageType: []*descriptorpb.DescriptorProto{{
+					Name:  Ref("User"),
+					Field: user,
+				}},
+				EnumType: []*descriptorpb.EnumDescriptorProto{
+					{Name: Ref("Status"), Value: status},
+					{Name: Ref("Kind"), Value: kind},
+				},
+			}},
+		}
+	}
+
+	ms := New("")
+
+	add := func(id int, fds *descriptorpb.FileDescriptorSet, sqls ...string) {
+		data := &jimmyv1.Migration{
+			FileDescriptorSets: map[string]*descriptorpb.FileDescriptorSet{"test": fds},
+		}
+		for _, sql := range sqls {
+			data.Upgrade = append(data.Upgrade, &jimmyv1.Statement{Sql: sql, FileDescriptorSet: Ref("test")})
+		}
+		ms.migrations[id] = newMigration(ms, id, "", data)
+		ms.latestID = id
+	}
+
+	add(1, fileDescriptorSet(
+		[]*descriptorpb.FieldDescriptorProto{
+			field("id", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+			field("name", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+			field("age", 3, descriptorpb.FieldDescriptorProto_TYPE_INT32, ""),
+			field("status", 4, descriptorpb.FieldDescriptorProto_TYPE_ENUM, ".test.v1.Status"),
+			field("email", 5, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+		},
+		[]*descriptorpb.EnumValueDescriptorProto{value("UNKNOWN", 0), value("ACTIVE", 1)},
+		[]*descriptorpb.EnumValueDescriptorProto{value("NONE", 0), value("BASIC", 1)},
+	),
+		"CREATE PROTO BUNDLE (test.v1.User, test.v1.Status, test.v1.Kind)",
+		"CREATE TABLE users (id STRING(MAX), user test.v1.User) PRIMARY KEY (id)",
+	)
+
+	problems, err := ms.LintProtos()
+	require.NoError(t, err)
+	require.Empty(t, problems)
+
+	add(2, fileDescriptorSet(
+		[]*descriptorpb.FieldDescriptorProto{
+			field("id", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+			field("age", 3, descriptorpb.FieldDescriptorProto_TYPE_INT64, ""),
+			field("status", 4, descriptorpb.FieldDescriptorProto_TYPE_ENUM, ".test.v1.Status"),
+			field("email", 6, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+		},
+		[]*descriptorpb.EnumValueDescriptorProto{value("UNKNOWN", 0)},
+		[]*descriptorpb.EnumValueDescriptorProto{value("NONE", 0)},
+	),
+		"ALTER PROTO BUNDLE UPDATE (test.v1.User, test.v1.Status, test.v1.Kind)",
+	)
+
+	problems, err = ms.LintProtos()
+	require.NoError(t, err)
+
+	var messages []string
+	for _, problem := range problems {
+		messages = append(messages, problem.Type+": "+problem.Message)
+	}
+
+	require.Equal(t, []string{
+		`test.v1.Status: enum value ACTIVE (1) removed`,
+		`test.v1.User: field "name" (2) removed`,
+		`test.v1.User: field "age" (3) type changed from int32 to int64`,
+		`test.v1.User: field "email" renumbered from 5 to 6`,
+	}, messages)
+}
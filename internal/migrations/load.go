@@ -2,8 +2,11 @@ package migrations
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -11,7 +14,22 @@ import (
 	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
 )
 
-func (ms *Migrations) Load(_ context.Context) error {
+var fileNamePattern = regexp.MustCompile(`^([0-9]+)_([a-z0-9_]+)` + regexp.QuoteMeta(constants.FileExt) + `$`)
+
+// Load reads the configuration and migration files, failing on invalid or
+// conflicting migration files.
+func (ms *Migrations) Load(ctx context.Context) error {
+	err := ms.LoadAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	return errors.Join(slices.Concat(ms.problems, ms.conflictProblems())...)
+}
+
+// LoadAll reads the configuration and migration files, collecting invalid
+// and conflicting migration files for Validate and Renumber.
+func (ms *Migrations) LoadAll(_ context.Context) error {
 	err := checkFile(ms.Path, "config")
 	if err != nil {
 		return err
@@ -34,31 +52,37 @@ func (ms *Migrations) Load(_ context.Context) error {
 
 		fileName := file.Name()
 
-		if !strings.HasSuffix(fileName, constants.FileExt) {
+		if !strings.HasSuffix(fileName, constants.FileExt) || strings.HasPrefix(fileName, ".") {
 			continue
 		}
 
-		idx := strings.Index(fileName, "_")
-		if idx == -1 {
+		match := fileNamePattern.FindStringSubmatch(fileName)
+		if match == nil || Slugify(match[2]) != match[2] {
+			ms.problems = append(ms.problems, fmt.Errorf(
+				"%q isn't a valid migration file name, expected <id>_<slug>%s",
+				fileName,
+				constants.FileExt,
+			))
 			continue
 		}
 
-		id, err := strconv.Atoi(fileName[:idx])
-		if err != nil {
+		id, err := strconv.Atoi(match[1])
+		if err != nil || id <= 0 {
+			ms.problems = append(ms.problems, fmt.Errorf("%q has an invalid migration ID", fileName))
 			continue
 		}
 
-		m, err := ms.Get(id)
-		if err == nil {
-			return fmt.Errorf("migration %d has conflicting migration files %q and %q",
-				id, fileName, m.fileName)
-		}
-
-		m = newMigration(ms, id, fileName, &jimmyv1.Migration{})
+		m := newMigration(ms, id, fileName, &jimmyv1.Migration{})
 
 		err = Unmarshal(m.Path(), m.data)
 		if err != nil {
-			return err
+			ms.problems = append(ms.problems, err)
+			continue
+		}
+
+		if _, found := ms.migrations[id]; found {
+			ms.conflicts = append(ms.conflicts, m)
+			continue
 		}
 
 		ms.setMigration(m)
@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
+)
+
+// loadPath returns the path of a load statement's file, which is relative
+// to the migration file.
+func (m *Migration) loadPath(s *jimmyv1.Statement) string {
+	return filepath.Join(filepath.Dir(m.Path()), s.GetLoad().GetFile())
+}
+
+// runLoad writes the rows of a load statement's file to its table.
+func (ms *Migrations) runLoad(ctx context.Context, m *Migration, s *jimmyv1.Statement) error {
+	path := m.loadPath(s)
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	rows, err := readRows(path, b)
+	if err != nil {
+		return err
+	}
+
+	columns, err := ms.columnTypes(ctx, s.GetLoad().GetTable())
+	if err != nil {
+		return fmt.Errorf("migration %d load %q: %w", m.ID(), s.GetLoad().GetFile(), err)
+	}
+
+	err = ms.writeRows(ctx, s.GetLoad().GetTable(), columns, rows)
+	if err != nil {
+		return fmt.Errorf("migration %d load %q %w", m.ID(), s.GetLoad().GetFile(), err)
+	}
+
+	return nil
+}
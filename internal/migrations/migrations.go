@@ -2,11 +2,15 @@ package migrations
 
 import (
 	"fmt"
+	"iter"
+	"maps"
 	"os"
+	"slices"
 
 	"cloud.google.com/go/spanner"
 	database "cloud.google.com/go/spanner/admin/database/apiv1"
 	instance "cloud.google.com/go/spanner/admin/instance/apiv1"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/silas/jimmy/internal/constants"
 	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
@@ -16,14 +20,21 @@ type Migrations struct {
 	Path   string
 	Config *jimmyv1.Config
 
+	// Env is the active user-defined environment.
+	Env string
+
 	emulator   bool
 	migrations map[int]*Migration
 	squash     map[int]int
 	latestID   int
+	problems   []error
+	conflicts  []*Migration
 
 	instanceAdmin *instance.InstanceAdminClient
 	databaseAdmin *database.DatabaseAdminClient
 	database      *spanner.Client
+	backupAdmin   BackupAdmin
+	exec          Executor
 
 	instanceEnsured bool
 	databaseEnsured bool
@@ -60,10 +71,67 @@ func (ms *Migrations) Close() {
 	}
 }
 
+// SetEnv sets the active user-defined environment.
+func (ms *Migrations) SetEnv(env string) error {
+	err := ms.checkEnvs(env)
+	if err != nil {
+		return err
+	}
+
+	ms.Env = env
+
+	return nil
+}
+
+// SetTarget applies the database and environment of a named target.
+func (ms *Migrations) SetTarget(name string) error {
+	target, found := ms.Config.GetTargets()[name]
+	if !found {
+		return fmt.Errorf("%q target not found", name)
+	}
+
+	if target.ProjectId != "" {
+		ms.Config.ProjectId = target.ProjectId
+	}
+
+	if target.InstanceId != "" {
+		ms.Config.InstanceId = target.InstanceId
+	}
+
+	if target.DatabaseId != "" {
+		ms.Config.DatabaseId = target.DatabaseId
+	}
+
+	if target.Env != "" {
+		ms.Env = target.Env
+	}
+
+	if target.DatabaseOptions != nil {
+		if ms.Config.DatabaseOptions == nil {
+			ms.Config.DatabaseOptions = &jimmyv1.DatabaseOptions{}
+		}
+
+		proto.Merge(ms.Config.DatabaseOptions, target.DatabaseOptions)
+	}
+
+	return nil
+}
+
 func (ms *Migrations) LatestID() int {
 	return ms.latestID
 }
 
+// All returns the loaded migrations in ID order.
+func (ms *Migrations) All() iter.Seq[*Migration] {
+	return func(yield func(*Migration) bool) {
+		for _, id := range slices.Sorted(maps.Keys(ms.migrations)) {
+			if !yield(ms.migrations[id]) {
+				return
+			}
+		}
+	}
+}
+
 func (ms *Migrations) Get(id int) (*Migration, error) {
 	m := ms.migrations[id]
 	if m == nil {
@@ -73,8 +141,12 @@ func (ms *Migrations) Get(id int) (*Migration, error) {
 	return m, nil
 }
 
+func (ms *Migrations) ProjectName() string {
+	return fmt.Sprintf("projects/%s", ms.Config.ProjectId)
+}
+
 func (ms *Migrations) InstancesName() string {
-	return fmt.Sprintf("projects/%s/instances", ms.Config.ProjectId)
+	return fmt.Sprintf("%s/instances", ms.ProjectName())
 }
 
 func (ms *Migrations) InstanceName() string {
@@ -416,6 +416,27 @@ CREATE TABLE hello_world (
 	return h
 }
 
+// offlineHelper returns initialized migrations that don't connect to the
+// emulator.
+func offlineHelper(t *testing.T) *helperData {
+	tmpDir := t.TempDir()
+
+	h := &helperData{
+		Ctx:  context.Background(),
+		Path: tmpDir,
+	}
+
+	h.Migrations = migrations.New(path.Join(tmpDir, constants.ConfigFile))
+	h.Migrations.Config.Path = path.Join(tmpDir, constants.MigrationsPath)
+	h.Migrations.Config.ProjectId = "test"
+	h.Migrations.Config.InstanceId = "test"
+	h.Migrations.Config.DatabaseId = "test"
+
+	require.NoError(t, h.Migrations.Init(h.Ctx))
+
+	return h
+}
+
 type helperData struct {
 	Ctx        context.Context
 	Path       string
@@ -465,3 +486,444 @@ type Record struct {
 	StartTime    time.Time
 	CompleteTime time.Time
 }
+
+func TestMigrations_OutOfOrder(t *testing.T) {
+	h := helper(t)
+
+	err := h.Migrations.Init(h.Ctx)
+	require.NoError(t, err)
+
+	_, err = h.Migrations.Create(h.Ctx, migrations.CreateInput{
+		Name:       "init",
+		TemplateID: "create-table",
+	})
+	require.NoError(t, err)
+
+	for _, id := range []string{"two", "three"} {
+		_, err = h.Migrations.Create(h.Ctx, migrations.CreateInput{
+			Name: "insert " + id,
+			SQL:  fmt.Sprintf(`INSERT INTO test (id, update_time) VALUES ("%s", CURRENT_TIMESTAMP)`, id),
+		})
+		require.NoError(t, err)
+	}
+
+	err = h.Migrations.Upgrade(h.Ctx)
+	require.NoError(t, err)
+
+	status, err := h.Migrations.Status(h.Ctx)
+	require.NoError(t, err)
+	require.Equal(t, 3, status.CurrentID)
+	require.Empty(t, status.Pending)
+
+	// simulate a migration merged after a later migration was applied
+	db, err := h.Migrations.Database(h.Ctx)
+	require.NoError(t, err)
+
+	_, err = db.Apply(h.Ctx, []*spanner.Mutation{
+		spanner.Delete(h.Migrations.Config.Table, spanner.Key{int64(2)}),
+		spanner.Delete("test", spanner.Key{"two"}),
+	})
+	require.NoError(t, err)
+
+	status, err = h.Migrations.Status(h.Ctx)
+	require.NoError(t, err)
+	require.Equal(t, 3, status.CurrentID)
+	require.Len(t, status.Pending, 1)
+	require.Equal(t, 2, status.Pending[0].ID())
+	require.Equal(t, status.Pending, status.OutOfOrder)
+
+	err = h.Migrations.Upgrade(h.Ctx)
+	require.ErrorIs(t, err, migrations.ErrOutOfOrder)
+
+	err = h.Migrations.Upgrade(h.Ctx, migrations.UpgradeAllowOutOfOrder(true))
+	require.NoError(t, err)
+
+	records, err := h.records()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+
+	status, err = h.Migrations.Status(h.Ctx)
+	require.NoError(t, err)
+	require.Empty(t, status.Pending)
+}
+
+func TestMigrations_When(t *testing.T) {
+	h := helper(t)
+
+	err := h.Migrations.Init(h.Ctx)
+	require.NoError(t, err)
+
+	_, err = h.Migrations.Create(h.Ctx, migrations.CreateInput{
+		Name:       "init",
+		TemplateID: "create-table",
+	})
+	require.NoError(t, err)
+
+	m, err := h.Migrations.Create(h.Ctx, migrations.CreateInput{
+		Name: "insert",
+		SQL:  `INSERT INTO test (id, update_time) VALUES ("yes", CURRENT_TIMESTAMP)`,
+		When: `SELECT COUNT(*) > 0 FROM information_schema.tables WHERE table_name = "test"`,
+	})
+	require.NoError(t, err)
+
+	err = h.Migrations.AddUpgrade(h.Ctx, migrations.AddUpgradeInput{
+		ID:   m.ID(),
+		SQL:  `INSERT INTO test (id, update_time) VALUES ("no", CURRENT_TIMESTAMP)`,
+		When: `SELECT COUNT(*) > 0 FROM information_schema.tables WHERE table_name = "missing"`,
+	})
+	require.NoError(t, err)
+
+	plan, err := h.Migrations.Plan(h.Ctx)
+	require.NoError(t, err)
+	require.Len(t, plan.Migrations, 2)
+	require.Len(t, plan.Migrations[1].Statements, 2)
+	require.False(t, *plan.Migrations[1].Statements[0].When)
+	require.False(t, *plan.Migrations[1].Statements[1].When)
+
+	results := map[int]bool{}
+
+	err = h.Migrations.Upgrade(
+		h.Ctx,
+		migrations.UpgradeOnWhen(func(m *migrations.Migration, pos int, result bool) {
+			results[pos] = result
+		}),
+	)
+	require.NoError(t, err)
+	require.Equal(t, map[int]bool{0: true, 1: false}, results)
+
+	db, err := h.Migrations.Database(h.Ctx)
+	require.NoError(t, err)
+
+	var ids []string
+
+	err = db.Single().Read(h.Ctx, "test", spanner.AllKeys(), []string{"id"}).Do(func(r *spanner.Row) error {
+		var id string
+
+		err := r.Column(0, &id)
+		if err != nil {
+			return err
+		}
+
+		ids = append(ids, id)
+
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"yes"}, ids)
+}
+
+func TestMigrations_Assert(t *testing.T) {
+	h := helper(t)
+
+	err := h.Migrations.Init(h.Ctx)
+	require.NoError(t, err)
+
+	_, err = h.Migrations.Create(h.Ctx, migrations.CreateInput{
+		Name:       "init",
+		TemplateID: "create-table",
+	})
+	require.NoError(t, err)
+
+	m, err := h.Migrations.Create(h.Ctx, migrations.CreateInput{
+		Name: "assert empty",
+		SQL:  `SELECT COUNT(*) = 0 FROM test`,
+		Type: jimmyv1.Type_ASSERT,
+	})
+	require.NoError(t, err)
+
+	err = h.Migrations.AddUpgrade(h.Ctx, migrations.AddUpgradeInput{
+		ID:   m.ID(),
+		SQL:  `SELECT false FROM test WHERE id IS NULL`,
+		Type: jimmyv1.Type_ASSERT,
+	})
+	require.NoError(t, err)
+
+	var types []jimmyv1.Type
+
+	err = h.Migrations.Upgrade(
+		h.Ctx,
+		migrations.UpgradeOnBatch(func(m *migrations.Migration, batch *migrations.Batch) {
+			types = append(types, batch.Statements[0].Type)
+		}),
+	)
+	require.NoError(t, err)
+	require.Equal(t, []jimmyv1.Type{jimmyv1.Type_DDL, jimmyv1.Type_ASSERT}, types)
+
+	m, err = h.Migrations.Create(h.Ctx, migrations.CreateInput{
+		Name: "assert not empty",
+		SQL:  `SELECT COUNT(*) > 0 FROM test`,
+		Type: jimmyv1.Type_ASSERT,
+	})
+	require.NoError(t, err)
+
+	err = h.Migrations.AddUpgrade(h.Ctx, migrations.AddUpgradeInput{
+		ID:  m.ID(),
+		SQL: `DROP TABLE test`,
+	})
+	require.NoError(t, err)
+
+	err = h.Migrations.Upgrade(h.Ctx)
+	require.ErrorIs(t, err, migrations.ErrAssertion)
+
+	// leading assertions run before the migration is started
+	status, err := h.Migrations.Status(h.Ctx)
+	require.NoError(t, err)
+	require.Empty(t, status.Incomplete)
+	require.Len(t, status.Pending, 1)
+	require.Equal(t, m.ID(), status.Pending[0].ID())
+}
+
+func TestMigrations_BatchedDML(t *testing.T) {
+	h := helper(t)
+
+	err := h.Migrations.Init(h.Ctx)
+	require.NoError(t, err)
+
+	m, err := h.Migrations.Create(h.Ctx, migrations.CreateInput{
+		Name:       "init",
+		TemplateID: "create-table",
+	})
+	require.NoError(t, err)
+
+	for i := range 5 {
+		err = h.Migrations.AddUpgrade(h.Ctx, migrations.AddUpgradeInput{
+			ID:  m.ID(),
+			SQL: fmt.Sprintf(`INSERT INTO test (id, update_time) VALUES ("%d", CURRENT_TIMESTAMP)`, i),
+		})
+		require.NoError(t, err)
+	}
+
+	_, err = h.Migrations.Create(h.Ctx, migrations.CreateInput{
+		Name:    "delete",
+		SQL:     `DELETE FROM test WHERE id IN (SELECT id FROM test LIMIT @batch_size)`,
+		Type:    jimmyv1.Type_BATCHED_DML,
+		Batched: &jimmyv1.BatchedOptions{Size: 2},
+	})
+	require.NoError(t, err)
+
+	var rowCounts []int64
+
+	err = h.Migrations.Upgrade(
+		h.Ctx,
+		migrations.UpgradeOnProgress(func(m *migrations.Migration, s *jimmyv1.Statement, iteration int, rowCount int64) {
+			require.Equal(t, len(rowCounts)+1, iteration)
+			rowCounts = append(rowCounts, rowCount)
+		}),
+	)
+	require.NoError(t, err)
+	require.Equal(t, []int64{2, 2, 1, 0}, rowCounts)
+}
+
+func TestMigrations_Load(t *testing.T) {
+	h := helper(t)
+
+	err := h.Migrations.Init(h.Ctx)
+	require.NoError(t, err)
+
+	m, err := h.Migrations.Create(h.Ctx, migrations.CreateInput{
+		Name:       "init",
+		TemplateID: "create-table",
+	})
+	require.NoError(t, err)
+
+	file := path.Join(t.TempDir(), "test.ndjson")
+
+	err = os.WriteFile(file, []byte(
+		`{"id": "one", "update_time": "2024-01-02T03:04:05Z"}`+"\n"+
+			`{"id": "two", "update_time": "PENDING_COMMIT_TIMESTAMP()"}`+"\n",
+	), 0644)
+	require.NoError(t, err)
+
+	err = h.Migrations.AddLoad(h.Ctx, migrations.AddLoadInput{
+		ID:   m.ID(),
+		Path: file,
+	})
+	require.NoError(t, err)
+
+	_, err = os.Stat(strings.TrimSuffix(m.Path(), ".yaml") + "_test.ndjson")
+	require.NoError(t, err)
+
+	require.NoError(t, h.Migrations.Validate())
+
+	err = h.Migrations.Upgrade(h.Ctx)
+	require.NoError(t, err)
+
+	db, err := h.Migrations.Database(h.Ctx)
+	require.NoError(t, err)
+
+	var ids []string
+
+	err = db.Single().Read(h.Ctx, "test", spanner.AllKeys(), []string{"id"}).Do(func(r *spanner.Row) error {
+		var id string
+
+		err := r.Column(0, &id)
+		if err != nil {
+			return err
+		}
+
+		ids = append(ids, id)
+
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"one", "two"}, ids)
+}
+
+func TestMigrations_NoWait(t *testing.T) {
+	h := helper(t)
+
+	err := h.Migrations.Init(h.Ctx)
+	require.NoError(t, err)
+
+	_, err = h.Migrations.Create(h.Ctx, migrations.CreateInput{
+		Name:       "init",
+		TemplateID: "create-table",
+	})
+	require.NoError(t, err)
+
+	_, err = h.Migrations.Create(h.Ctx, migrations.CreateInput{
+		Name: "index",
+		SQL:  `CREATE INDEX idx_test_name ON test (name)`,
+	})
+	require.NoError(t, err)
+
+	var operations []string
+
+	err = h.Migrations.Upgrade(
+		h.Ctx,
+		migrations.UpgradeNoWait(true),
+		migrations.UpgradeOnOperation(func(m *migrations.Migration, name string) {
+			require.Equal(t, 1, m.ID())
+			operations = append(operations, name)
+		}),
+	)
+	require.NoError(t, err)
+	require.Len(t, operations, 1)
+
+	status, err := h.Migrations.Status(h.Ctx)
+	require.NoError(t, err)
+	require.Equal(t, []int{1}, status.Incomplete)
+
+	ops, err := h.Migrations.WaitOperations(h.Ctx, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, ops, 1)
+	require.Equal(t, 1, ops[0].MigrationID)
+	require.Equal(t, operations[0], ops[0].Name)
+
+	ops, err = h.Migrations.Operations(h.Ctx)
+	require.NoError(t, err)
+	require.Empty(t, ops)
+
+	err = h.Migrations.Upgrade(h.Ctx)
+	require.NoError(t, err)
+
+	records, err := h.records()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+}
+
+func TestMigrations_Reattach(t *testing.T) {
+	h := helper(t)
+
+	err := h.Migrations.Init(h.Ctx)
+	require.NoError(t, err)
+
+	m, err := h.Migrations.Create(h.Ctx, migrations.CreateInput{
+		Name:       "init",
+		TemplateID: "create-table",
+	})
+	require.NoError(t, err)
+
+	err = h.Migrations.AddUpgrade(h.Ctx, migrations.AddUpgradeInput{
+		ID:  m.ID(),
+		SQL: `CREATE INDEX idx_test_name ON test (name)`,
+	})
+	require.NoError(t, err)
+
+	_, err = h.Migrations.Create(h.Ctx, migrations.CreateInput{
+		Name: "insert",
+		SQL:  `INSERT INTO test (id, update_time) VALUES ("one", CURRENT_TIMESTAMP)`,
+	})
+	require.NoError(t, err)
+
+	// stop after submitting the DDL, as if the upgrade was interrupted
+	err = h.Migrations.Upgrade(h.Ctx, migrations.UpgradeNoWait(true))
+	require.NoError(t, err)
+
+	var reattached []int
+
+	err = h.Migrations.Upgrade(
+		h.Ctx,
+		migrations.UpgradeOnReattach(func(m *migrations.Migration, name string) {
+			require.NotEmpty(t, name)
+			reattached = append(reattached, m.ID())
+		}),
+	)
+	require.NoError(t, err)
+	require.Equal(t, []int{1}, reattached)
+
+	status, err := h.Migrations.Status(h.Ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, status.CurrentID)
+	require.Empty(t, status.Incomplete)
+	require.Empty(t, status.Pending)
+}
+
+func TestMigrations_Import(t *testing.T) {
+	h := helper(t)
+
+	err := h.Migrations.Init(h.Ctx)
+	require.NoError(t, err)
+
+	createUsers := "CREATE TABLE users (id INT64 NOT NULL) PRIMARY KEY (id)"
+
+	// database previously managed by wrench
+	m, err := h.Migrations.Create(h.Ctx, migrations.CreateInput{
+		Name: "wrench",
+		SQL:  "CREATE TABLE SchemaMigrations (Version INT64 NOT NULL, Dirty BOOL NOT NULL) PRIMARY KEY (Version)",
+	})
+	require.NoError(t, err)
+
+	for _, sql := range []string{
+		createUsers,
+		"INSERT INTO SchemaMigrations (Version, Dirty) VALUES (1, false)",
+	} {
+		err = h.Migrations.AddUpgrade(h.Ctx, migrations.AddUpgradeInput{ID: m.ID(), SQL: sql})
+		require.NoError(t, err)
+	}
+
+	err = h.Migrations.Upgrade(h.Ctx)
+	require.NoError(t, err)
+
+	importPath := t.TempDir()
+
+	for name, content := range map[string]string{
+		"000001_create_users.sql": createUsers + ";",
+		"000002_add_user.sql":     "INSERT INTO users (id) VALUES (1);",
+	} {
+		err = os.WriteFile(path.Join(importPath, name), []byte(content), 0644)
+		require.NoError(t, err)
+	}
+
+	imported, err := h.Migrations.Import(h.Ctx, migrations.ImportInput{
+		From:        constants.ImportWrench,
+		Path:        importPath,
+		MarkApplied: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, imported, 2)
+	require.True(t, imported[0].Applied)
+	require.False(t, imported[1].Applied)
+
+	status, err := h.Migrations.Status(h.Ctx)
+	require.NoError(t, err)
+	require.Len(t, status.Pending, 1)
+	require.Equal(t, imported[1].Migration.ID(), status.Pending[0].ID())
+
+	err = h.Migrations.Upgrade(h.Ctx)
+	require.NoError(t, err)
+
+	applied, err := h.Migrations.AppliedIDs(h.Ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[int]bool{1: true, 2: true, 3: true}, applied)
+}
@@ -0,0 +1,398 @@
+// Package migrationstest provides an in-memory executor for running
+// migrations without Spanner.
+package migrationstest
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"cloud.google.com/go/spanner/admin/database/apiv1/databasepb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/silas/jimmy/internal/migrations"
+)
+
+var createTable = regexp.MustCompile(`(?i)^CREATE\s+TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?(\w+)`)
+
+// Call is a recorded statement execution.
+type Call struct {
+	Method     string
+	Statements []string
+	Mutations  []*spanner.Mutation
+}
+
+// Executor records statements instead of running them.
+type Executor struct {
+	// Results contains the values returned by queries, keyed by SQL.
+	// Queries without a result return no rows.
+	Results map[string]bool
+
+	// RowCounts contains the row counts returned by successive runs of a
+	// DML statement, keyed by SQL. Statements without row counts affect no
+	// rows.
+	RowCounts map[string][]int64
+
+	// Errors contains the errors returned by statements, keyed by SQL.
+	Errors map[string]error
+
+	// Columns contains the column types returned for tables, keyed by table
+	// and then column name.
+	Columns map[string]map[string]string
+
+	// Options contains the database options, keyed by lowercase name.
+	Options map[string]string
+
+	// Checksums contains the checksums of written seed files, keyed by
+	// seeds table and then path.
+	Checksums map[string]map[string]string
+
+	// Versions contains the rows of the version tables of other migration
+	// tools, keyed by table and then version, with whether it's dirty.
+	Versions map[string]map[int64]bool
+
+	mu               sync.Mutex
+	calls            []*Call
+	ddl              []string
+	protoDescriptors []byte
+	operations       map[string][]string
+	tables           map[string]map[int]*migrations.TrackedMigration
+}
+
+var _ migrations.Executor = (*Executor)(nil)
+
+func NewExecutor() *Executor {
+	return &Executor{
+		Results:    map[string]bool{},
+		RowCounts:  map[string][]int64{},
+		Errors:     map[string]error{},
+		Columns:    map[string]map[string]string{},
+		Options:    map[string]string{},
+		Checksums:  map[string]map[string]string{},
+		Versions:   map[string]map[int64]bool{},
+		operations: map[string][]string{},
+		tables:     map[string]map[int]*migrations.TrackedMigration{},
+	}
+}
+
+// Calls returns the recorded statement executions.
+func (e *Executor) Calls() []*Call {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return slices.Clone(e.calls)
+}
+
+// DDL returns the applied DDL statements.
+func (e *Executor) DDL() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return slices.Clone(e.ddl)
+}
+
+func (e *Executor) EnsureDatabase(_ context.Context) error {
+	return nil
+}
+
+func (e *Executor) EnsureTable(_ context.Context, table string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.tables[table] == nil {
+		e.tables[table] = map[int]*migrations.TrackedMigration{}
+	}
+
+	return nil
+}
+
+func (e *Executor) UpdateDDL(_ context.Context, statements []string, protoDescriptors []byte) (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	err := e.record("UpdateDDL", statements...)
+	if err != nil {
+		return "", err
+	}
+
+	e.ddl = append(e.ddl, e.calls[len(e.calls)-1].Statements...)
+
+	if protoDescriptors != nil {
+		e.protoDescriptors = protoDescriptors
+	}
+
+	name := fmt.Sprintf("operations/%d", len(e.operations)+1)
+	e.operations[name] = e.calls[len(e.calls)-1].Statements
+
+	return name, nil
+}
+
+func (e *Executor) WaitDDL(_ context.Context, operation string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, found := e.operations[operation]; !found {
+		return fmt.Errorf("operation %q not found", operation)
+	}
+
+	return nil
+}
+
+// GetDDLOperation returns a finished operation with a commit timestamp for
+// each of its statements.
+func (e *Executor) GetDDLOperation(_ context.Context, operation string) (*migrations.DDLOperation, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	statements, found := e.operations[operation]
+	if !found {
+		return nil, fmt.Errorf("operation %q not found", operation)
+	}
+
+	metadata := &databasepb.UpdateDatabaseDdlMetadata{
+		Statements: slices.Clone(statements),
+	}
+
+	for range statements {
+		metadata.CommitTimestamps = append(metadata.CommitTimestamps, timestamppb.Now())
+	}
+
+	return &migrations.DDLOperation{
+		Name:     operation,
+		Done:     true,
+		Metadata: metadata,
+	}, nil
+}
+
+// RunningDDLOperations returns no operations, as operations finish as soon
+// as they're submitted.
+func (e *Executor) RunningDDLOperations(_ context.Context) ([]*migrations.DDLOperation, error) {
+	return nil, nil
+}
+
+func (e *Executor) DropDatabase(_ context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.record("DropDatabase")
+}
+
+func (e *Executor) BatchUpdate(_ context.Context, statements []spanner.Statement) ([]int64, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var sqls []string
+
+	for _, s := range statements {
+		sqls = append(sqls, s.SQL)
+	}
+
+	err := e.record("BatchUpdate", sqls...)
+	if err != nil {
+		return nil, err
+	}
+
+	var rowCounts []int64
+
+	for _, sql := range sqls {
+		rowCounts = append(rowCounts, e.rowCount(sql))
+	}
+
+	return rowCounts, nil
+}
+
+func (e *Executor) PartitionedUpdate(_ context.Context, statement spanner.Statement) (int64, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	err := e.record("PartitionedUpdate", statement.SQL)
+	if err != nil {
+		return 0, err
+	}
+
+	return e.rowCount(statement.SQL), nil
+}
+
+func (e *Executor) Apply(_ context.Context, mutations []*spanner.Mutation) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	err := e.record("Apply")
+	if err != nil {
+		return err
+	}
+
+	e.calls[len(e.calls)-1].Mutations = slices.Clone(mutations)
+
+	return nil
+}
+
+func (e *Executor) QueryBool(_ context.Context, statement spanner.Statement) (spanner.NullBool, bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	err := e.record("QueryBool", statement.SQL)
+	if err != nil {
+		return spanner.NullBool{}, false, err
+	}
+
+	result, found := e.Results[strings.TrimSpace(statement.SQL)]
+	if !found {
+		return spanner.NullBool{}, false, nil
+	}
+
+	return spanner.NullBool{Bool: result, Valid: true}, true, nil
+}
+
+func (e *Executor) ColumnTypes(_ context.Context, table string) (map[string]string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return maps.Clone(e.Columns[table]), nil
+}
+
+func (e *Executor) DatabaseOptions(_ context.Context) (map[string]string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return maps.Clone(e.Options), nil
+}
+
+func (e *Executor) ReadTimestamp(_ context.Context) (time.Time, error) {
+	return time.Now(), nil
+}
+
+// TableExists returns whether the table has columns, is a migration or
+// version table, or was created by a DDL statement.
+func (e *Executor) TableExists(_ context.Context, table string) (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.Columns[table]) > 0 || e.tables[table] != nil || e.Versions[table] != nil {
+		return true, nil
+	}
+
+	for _, sql := range e.ddl {
+		match := createTable.FindStringSubmatch(sql)
+		if match != nil && match[1] == table {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (e *Executor) SeedChecksums(_ context.Context, table string) (map[string]string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return maps.Clone(e.Checksums[table]), nil
+}
+
+func (e *Executor) ImportVersions(_ context.Context, table string) (map[int64]bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return maps.Clone(e.Versions[table]), nil
+}
+
+func (e *Executor) TrackedMigrations(_ context.Context, table string) ([]*migrations.TrackedMigration, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	rows := e.tables[table]
+
+	var tracked []*migrations.TrackedMigration
+
+	for _, id := range slices.Sorted(maps.Keys(rows)) {
+		row := *rows[id]
+		tracked = append(tracked, &row)
+	}
+
+	return tracked, nil
+}
+
+func (e *Executor) StartMigration(_ context.Context, table string, id int) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	rows, found := e.tables[table]
+	if !found {
+		return fmt.Errorf("table %q not found", table)
+	}
+
+	if _, found := rows[id]; found {
+		return fmt.Errorf("migration %d already exists", id)
+	}
+
+	rows[id] = &migrations.TrackedMigration{ID: id}
+
+	return nil
+}
+
+func (e *Executor) UpdateMigration(_ context.Context, table string, id int, values map[string]any) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	row, found := e.tables[table][id]
+	if !found {
+		return fmt.Errorf("migration %d not found", id)
+	}
+
+	if _, found := values["complete_time"]; found {
+		row.Complete = true
+	}
+
+	if operation, found := values["operation"].(spanner.NullString); found {
+		row.Operation = operation.StringVal
+	}
+
+	return nil
+}
+
+func (e *Executor) GetDDL(_ context.Context) ([]string, []byte, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return slices.Clone(e.ddl), e.protoDescriptors, nil
+}
+
+// record appends a call with trimmed statements, returning the error
+// configured for its first failing statement.
+func (e *Executor) record(method string, statements ...string) error {
+	call := &Call{Method: method}
+
+	for _, sql := range statements {
+		call.Statements = append(call.Statements, strings.TrimSpace(sql))
+	}
+
+	e.calls = append(e.calls, call)
+
+	for _, sql := range call.Statements {
+		err := e.Errors[sql]
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *Executor) rowCount(sql string) int64 {
+	key := strings.TrimSpace(sql)
+
+	rowCounts := e.RowCounts[key]
+	if len(rowCounts) == 0 {
+		return 0
+	}
+
+	e.RowCounts[key] = rowCounts[1:]
+
+	return rowCounts[0]
+}
@@ -0,0 +1,82 @@
+package migrations
+
+import (
+	"context"
+
+	"cloud.google.com/go/spanner"
+
+	"github.com/silas/jimmy/internal/constants"
+)
+
+// mutationBatch applies mutations in batches that stay well under the
+// Spanner per-commit mutation and size limits.
+type mutationBatch struct {
+	ms        *Migrations
+	mutations []*spanner.Mutation
+	cells     int
+	bytes     int
+}
+
+func (b *mutationBatch) add(ctx context.Context, m *spanner.Mutation, values []any) error {
+	cells := len(values)
+	bytes := 0
+
+	for _, v := range values {
+		bytes += valueSize(v)
+	}
+
+	if len(b.mutations) > 0 &&
+		(b.cells+cells > constants.MaxMutationCells || b.bytes+bytes > constants.MaxMutationBytes) {
+		err := b.flush(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	b.mutations = append(b.mutations, m)
+	b.cells += cells
+	b.bytes += bytes
+
+	return nil
+}
+
+func (b *mutationBatch) flush(ctx context.Context) error {
+	if len(b.mutations) == 0 {
+		return nil
+	}
+
+	err := b.ms.executor().Apply(ctx, b.mutations)
+	if err != nil {
+		return err
+	}
+
+	b.mutations = nil
+	b.cells = 0
+	b.bytes = 0
+
+	return nil
+}
+
+// valueSize estimates the encoded size of a mutation value.
+func valueSize(v any) int {
+	switch v := v.(type) {
+	case spanner.NullString:
+		return len(v.StringVal)
+	case []byte:
+		return len(v)
+	case []spanner.NullString:
+		size := 0
+		for _, s := range v {
+			size += len(s.StringVal)
+		}
+		return size
+	case [][]byte:
+		size := 0
+		for _, b := range v {
+			size += len(b)
+		}
+		return size
+	default:
+		return 8
+	}
+}
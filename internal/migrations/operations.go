@@ -0,0 +1,253 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"cloud.google.com/go/spanner/admin/database/apiv1/databasepb"
+
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
+)
+
+type Operation struct {
+	// MigrationID is the ID of the migration waiting for the operation.
+	MigrationID int
+
+	// Name is the name of the long-running operation.
+	Name string
+
+	// Done is whether the operation finished.
+	Done bool
+
+	// Err is the error the operation failed with.
+	Err error
+
+	// Metadata contains the statements and their progress.
+	Metadata *databasepb.UpdateDatabaseDdlMetadata
+}
+
+type OnOperation func(op *Operation)
+
+// Operations returns the DDL operations that incomplete migrations are
+// waiting for.
+func (ms *Migrations) Operations(ctx context.Context) ([]*Operation, error) {
+	err := ms.ensureAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := ms.operationNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var operations []*Operation
+
+	for _, id := range slices.Sorted(maps.Keys(names)) {
+		operation := &Operation{
+			MigrationID: id,
+			Name:        names[id],
+		}
+
+		op, err := ms.executor().GetDDLOperation(ctx, operation.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s: %w", operation.Name, err)
+		}
+
+		operation.Done = op.Done
+		operation.Err = op.Err
+		operation.Metadata = op.Metadata
+
+		operations = append(operations, operation)
+	}
+
+	return operations, nil
+}
+
+// WaitOperations waits for the DDL operations of incomplete migrations and
+// completes the migrations, running their remaining statements with the
+// upgrade options. UpgradeNoWait is ignored.
+func (ms *Migrations) WaitOperations(
+	ctx context.Context,
+	onWait, onComplete OnOperation,
+	opts ...UpgradeOption,
+) ([]*Operation, error) {
+	o := &upgradeOptions{}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	o.noWait = false
+
+	operations, err := ms.Operations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, operation := range operations {
+		m, err := ms.Get(operation.MigrationID)
+		if err != nil {
+			return nil, err
+		}
+
+		if onWait != nil {
+			onWait(operation)
+		}
+
+		_, err = ms.resumeMigration(ctx, m, o)
+		if err != nil {
+			return nil, err
+		}
+
+		operation.Done = true
+
+		if onComplete != nil {
+			onComplete(operation)
+		}
+	}
+
+	return operations, nil
+}
+
+// resumeMigration waits for the DDL operation an incomplete migration was
+// interrupted during, then runs the statements after it.
+func (ms *Migrations) resumeMigration(ctx context.Context, m *Migration, o *upgradeOptions) (string, error) {
+	id := m.ID()
+
+	names, err := ms.operationNames(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	name := names[id]
+	if name == "" {
+		name, err = ms.findOperation(ctx, m)
+		if err != nil {
+			return "", fmt.Errorf("migration %d is incomplete: %w", id, err)
+		}
+	}
+
+	if name == "" {
+		return "", fmt.Errorf("migration %d is incomplete", id)
+	}
+
+	if o.noWait {
+		op, err := ms.executor().GetDDLOperation(ctx, name)
+		if err != nil {
+			return "", fmt.Errorf("failed to get %s: %w", name, err)
+		}
+
+		if !op.Done {
+			return "", fmt.Errorf("%w: migration %d is waiting for %s", ErrPendingOperation, id, name)
+		}
+	}
+
+	if o.onReattach != nil {
+		o.onReattach(m, name)
+	}
+
+	err = ms.executor().WaitDDL(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("migration %d %s failed: %w", id, name, err)
+	}
+
+	op, err := ms.executor().GetDDLOperation(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s: %w", name, err)
+	}
+
+	metadata := op.Metadata
+	if metadata == nil || len(metadata.CommitTimestamps) < len(metadata.Statements) {
+		return "", fmt.Errorf("migration %d %s didn't commit all statements", id, name)
+	}
+
+	start, found := resumePosition(m, metadata.Statements)
+	if !found {
+		return "", fmt.Errorf("migration %d statements don't match %s", id, name)
+	}
+
+	err = ms.setOperation(ctx, id, "")
+	if err != nil {
+		return "", err
+	}
+
+	return ms.runMigration(ctx, m, start, o)
+}
+
+// findOperation returns the name of a running DDL operation for the
+// statements of a migration, for migrations interrupted before the
+// operation was recorded.
+func (ms *Migrations) findOperation(ctx context.Context, m *Migration) (string, error) {
+	operations, err := ms.executor().RunningDDLOperations(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	for _, op := range operations {
+		if _, found := resumePosition(m, op.Metadata.GetStatements()); found {
+			return op.Name, nil
+		}
+	}
+
+	return "", nil
+}
+
+// resumePosition returns the position after the upgrade statements run by
+// a DDL operation, skipping statements that may have been disabled.
+func resumePosition(m *Migration, statements []string) (int, bool) {
+	upgrade := m.data.GetUpgrade()
+
+	if len(statements) == 0 {
+		return 0, false
+	}
+
+	for i := range upgrade {
+		matched := 0
+
+		for j := i; j < len(upgrade); j++ {
+			s := upgrade[j]
+
+			if strings.TrimSpace(s.Sql) == strings.TrimSpace(statements[matched]) {
+				matched++
+
+				if matched == len(statements) {
+					return j + 1, true
+				}
+
+				continue
+			}
+
+			conditional := s.When != nil || s.Env != jimmyv1.Environment_ALL ||
+				len(s.Envs) > 0 || len(s.ExcludeEnvs) > 0
+
+			if matched == 0 || !conditional {
+				break
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// operationNames returns the names of the operations incomplete migrations
+// are waiting for, keyed by migration ID.
+func (ms *Migrations) operationNames(ctx context.Context) (map[int]string, error) {
+	tracked, err := ms.executor().TrackedMigrations(ctx, ms.Config.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[int]string{}
+
+	for _, t := range tracked {
+		if !t.Complete && t.Operation != "" {
+			names[t.ID] = t.Operation
+		}
+	}
+
+	return names, nil
+}
@@ -0,0 +1,79 @@
+package migrations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
+)
+
+func TestResumePosition(t *testing.T) {
+	m := &Migration{
+		data: &jimmyv1.Migration{
+			Upgrade: []*jimmyv1.Statement{
+				{Sql: "CREATE TABLE a (id INT64) PRIMARY KEY (id)\n"},
+				{Sql: "INSERT INTO a (id) VALUES (1)\n"},
+				{Sql: "CREATE INDEX a_id ON a (id)\n"},
+				{Sql: "CREATE INDEX a_prod ON a (id)\n", Envs: []string{"prod"}},
+				{Sql: "CREATE TABLE b (id INT64) PRIMARY KEY (id)\n"},
+				{Sql: "INSERT INTO b (id) VALUES (1)\n"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		statements []string
+		position   int
+		found      bool
+	}{
+		{
+			name:       "first",
+			statements: []string{"CREATE TABLE a (id INT64) PRIMARY KEY (id)"},
+			position:   1,
+			found:      true,
+		},
+		{
+			name: "all",
+			statements: []string{
+				"CREATE INDEX a_id ON a (id)",
+				"CREATE INDEX a_prod ON a (id)",
+				"CREATE TABLE b (id INT64) PRIMARY KEY (id)",
+			},
+			position: 5,
+			found:    true,
+		},
+		{
+			name: "skipped env",
+			statements: []string{
+				"CREATE INDEX a_id ON a (id)",
+				"CREATE TABLE b (id INT64) PRIMARY KEY (id)",
+			},
+			position: 5,
+			found:    true,
+		},
+		{
+			name: "not contiguous",
+			statements: []string{
+				"CREATE TABLE a (id INT64) PRIMARY KEY (id)",
+				"CREATE INDEX a_id ON a (id)",
+			},
+		},
+		{
+			name:       "missing",
+			statements: []string{"DROP TABLE a"},
+		},
+		{
+			name: "empty",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			position, found := resumePosition(m, test.statements)
+			require.Equal(t, test.found, found)
+			require.Equal(t, test.position, position)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package migrations
+
+import (
+	"context"
+
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
+)
+
+type Plan struct {
+	Status     *Status
+	Migrations []*PlanMigration
+}
+
+type PlanMigration struct {
+	Migration  *Migration
+	Statements []*PlanStatement
+}
+
+type PlanStatement struct {
+	Statement *jimmyv1.Statement
+
+	// Enabled is whether the statement runs in the current environment.
+	Enabled bool
+
+	// When is the result of the when query against the current schema, or
+	// nil if the statement doesn't have a when query or it failed.
+	When *bool
+
+	// WhenErr is the error returned by the when query.
+	WhenErr error
+}
+
+// Plan returns the statements an upgrade would run. When queries are
+// evaluated against the current schema, so they don't see the changes of
+// earlier pending statements.
+func (ms *Migrations) Plan(ctx context.Context) (*Plan, error) {
+	status, err := ms.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{Status: status}
+
+	for _, m := range status.Pending {
+		pm := &PlanMigration{Migration: m}
+
+		for _, s := range m.data.GetUpgrade() {
+			enabled, err := ms.isEnabled(ms.environment(), s)
+			if err != nil {
+				return nil, err
+			}
+
+			ps := &PlanStatement{
+				Statement: s,
+				Enabled:   enabled,
+			}
+
+			if enabled && s.When != nil {
+				result, err := ms.evaluateWhen(ctx, s)
+				if err != nil {
+					ps.WhenErr = err
+				} else {
+					ps.When = &result
+				}
+			}
+
+			pm.Statements = append(pm.Statements, ps)
+		}
+
+		plan.Migrations = append(plan.Migrations, pm)
+	}
+
+	return plan, nil
+}
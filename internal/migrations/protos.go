@@ -0,0 +1,471 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+var (
+	protoBundleClause = regexp.MustCompile(`(?i)(BUNDLE|INSERT|UPDATE|DELETE)\s*\(([^)]*)\)`)
+)
+
+// protoBundleTypes returns the types inserted or updated, and the types
+// deleted by a CREATE or ALTER PROTO BUNDLE statement.
+func protoBundleTypes(sql string) (types []string, deleted []string) {
+	if !isProtoDDL(sql) {
+		return nil, nil
+	}
+
+	for _, match := range protoBundleClause.FindAllStringSubmatch(sql, -1) {
+		for _, name := range strings.Split(match[2], ",") {
+			name = strings.Trim(strings.TrimSpace(name), "`")
+			if name == "" {
+				continue
+			}
+
+			if strings.EqualFold(match[1], "DELETE") {
+				deleted = append(deleted, name)
+			} else {
+				types = append(types, name)
+			}
+		}
+	}
+
+	return types, deleted
+}
+
+func readFileDescriptorSet(path string) (*descriptorpb.FileDescriptorSet, error) {
+	err := checkFile(path, "proto")
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fileDescriptorSet := &descriptorpb.FileDescriptorSet{}
+
+	err = proto.Unmarshal(b, fileDescriptorSet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %q file descriptor set: %w", path, err)
+	}
+
+	return fileDescriptorSet, nil
+}
+
+// compileProto compiles .proto source files into a file descriptor set
+// containing the files and all their dependencies.
+func compileProto(
+	ctx context.Context,
+	paths []string,
+	importPaths []string,
+) (*descriptorpb.FileDescriptorSet, error) {
+	if len(importPaths) == 0 {
+		importPaths = []string{"."}
+	}
+
+	var files []string
+
+	for _, path := range paths {
+		err := checkFile(path, "proto")
+		if err != nil {
+			return nil, err
+		}
+
+		file, err := importPath(path, importPaths)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, file)
+	}
+
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			ImportPaths: importPaths,
+		}),
+	}
+
+	results, err := compiler.Compile(ctx, files...)
+	if err != nil {
+		return nil, err
+	}
+
+	fileDescriptorSet := &descriptorpb.FileDescriptorSet{}
+	added := map[string]bool{}
+
+	var add func(fd protoreflect.FileDescriptor)
+	add = func(fd protoreflect.FileDescriptor) {
+		if added[fd.Path()] {
+			return
+		}
+		added[fd.Path()] = true
+
+		imports := fd.Imports()
+		for i := range imports.Len() {
+			add(imports.Get(i).FileDescriptor)
+		}
+
+		fileDescriptorSet.File = append(fileDescriptorSet.File, protodesc.ToFileDescriptorProto(fd))
+	}
+
+	for _, result := range results {
+		add(result)
+	}
+
+	return fileDescriptorSet, nil
+}
+
+func importPath(path string, importPaths []string) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	for _, importPath := range importPaths {
+		absImportPath, err := filepath.Abs(importPath)
+		if err != nil {
+			return "", err
+		}
+
+		rel, err := filepath.Rel(absImportPath, absPath)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+
+		return filepath.ToSlash(rel), nil
+	}
+
+	return "", fmt.Errorf("%q isn't within an import path", path)
+}
+
+// protoTypes returns the fully-qualified names of the messages and enums
+// defined in a file.
+func protoTypes(file *descriptorpb.FileDescriptorProto) []string {
+	var types []string
+
+	prefix := file.GetPackage()
+	if prefix != "" {
+		prefix += "."
+	}
+
+	var addMessages func(prefix string, messages []*descriptorpb.DescriptorProto)
+	addMessages = func(prefix string, messages []*descriptorpb.DescriptorProto) {
+		for _, message := range messages {
+			if message.GetOptions().GetMapEntry() {
+				continue
+			}
+
+			name := prefix + message.GetName()
+			types = append(types, name)
+
+			for _, enum := range message.EnumType {
+				types = append(types, name+"."+enum.GetName())
+			}
+
+			addMessages(name+".", message.NestedType)
+		}
+	}
+
+	for _, enum := range file.EnumType {
+		types = append(types, prefix+enum.GetName())
+	}
+
+	addMessages(prefix, file.MessageType)
+
+	return types
+}
+
+// filterFileDescriptorSet removes the top-level messages and enums that
+// aren't required by the given types, along with files left empty. Nested
+// types are kept with the message they're defined in.
+func filterFileDescriptorSet(
+	fileDescriptorSet *descriptorpb.FileDescriptorSet,
+	types []string,
+) (*descriptorpb.FileDescriptorSet, error) {
+	// top-level type by fully-qualified name, including nested types
+	owners := map[string]string{}
+	// file by top-level type
+	typeFiles := map[string]string{}
+	// top-level types referenced by each top-level type
+	references := map[string][]string{}
+
+	for _, file := range fileDescriptorSet.File {
+		prefix := file.GetPackage()
+		if prefix != "" {
+			prefix += "."
+		}
+
+		for _, enum := range file.EnumType {
+			name := prefix + enum.GetName()
+			owners[name] = name
+			typeFiles[name] = file.GetName()
+		}
+
+		for _, message := range file.MessageType {
+			name := prefix + message.GetName()
+			typeFiles[name] = file.GetName()
+
+			var walk func(fullName string, message *descriptorpb.DescriptorProto)
+			walk = func(fullName string, message *descriptorpb.DescriptorProto) {
+				owners[fullName] = name
+
+				for _, enum := range message.EnumType {
+					owners[fullName+"."+enum.GetName()] = name
+				}
+
+				for _, field := range message.Field {
+					if typeName := field.GetTypeName(); typeName != "" {
+						references[name] = append(references[name], strings.TrimPrefix(typeName, "."))
+					}
+				}
+
+				for _, nested := range message.NestedType {
+					walk(fullName+"."+nested.GetName(), nested)
+				}
+			}
+
+			walk(name, message)
+		}
+	}
+
+	required := map[string]bool{}
+
+	var require func(name string)
+	require = func(name string) {
+		if required[name] {
+			return
+		}
+		required[name] = true
+
+		for _, reference := range references[name] {
+			if owner, found := owners[reference]; found {
+				require(owner)
+			}
+		}
+	}
+
+	for _, name := range types {
+		owner, found := owners[strings.TrimPrefix(name, ".")]
+		if !found {
+			return nil, fmt.Errorf("%q type not found in file descriptor set", name)
+		}
+
+		require(owner)
+	}
+
+	// files referenced by the types kept in each file
+	fileDependencies := map[string]map[string]bool{}
+
+	for name := range required {
+		file := typeFiles[name]
+
+		if fileDependencies[file] == nil {
+			fileDependencies[file] = map[string]bool{}
+		}
+
+		for _, reference := range references[name] {
+			if dep := typeFiles[owners[reference]]; dep != "" && dep != file {
+				fileDependencies[file][dep] = true
+			}
+		}
+	}
+
+	filtered := &descriptorpb.FileDescriptorSet{}
+
+	for _, file := range fileDescriptorSet.File {
+		dependencies, found := fileDependencies[file.GetName()]
+		if !found {
+			continue
+		}
+
+		prefix := file.GetPackage()
+		if prefix != "" {
+			prefix += "."
+		}
+
+		pruned := proto.Clone(file).(*descriptorpb.FileDescriptorProto)
+		pruned.Service = nil
+		pruned.Extension = nil
+		pruned.PublicDependency = nil
+		pruned.WeakDependency = nil
+		pruned.SourceCodeInfo = nil
+
+		pruned.Dependency = slices.DeleteFunc(pruned.Dependency, func(dep string) bool {
+			return !dependencies[dep]
+		})
+
+		pruned.MessageType = slices.DeleteFunc(pruned.MessageType, func(message *descriptorpb.DescriptorProto) bool {
+			return !required[prefix+message.GetName()]
+		})
+
+		pruned.EnumType = slices.DeleteFunc(pruned.EnumType, func(enum *descriptorpb.EnumDescriptorProto) bool {
+			return !required[prefix+enum.GetName()]
+		})
+
+		filtered.File = append(filtered.File, pruned)
+	}
+
+	return filtered, nil
+}
+
+// bundleTypes returns the types referenced by the migration's proto bundle
+// statements using the named file descriptor set.
+func (m *Migration) bundleTypes(name string) []string {
+	var types []string
+
+	for _, s := range m.data.GetUpgrade() {
+		if s.GetFileDescriptorSet() != name {
+			continue
+		}
+
+		inserted, _ := protoBundleTypes(s.Sql)
+
+		for _, t := range inserted {
+			if !slices.Contains(types, t) {
+				types = append(types, t)
+			}
+		}
+	}
+
+	return types
+}
+
+// protoBundle returns the proto bundle types in effect before the
+// statements of the given migration, following the same sequence as an
+// upgrade of a new database.
+func (ms *Migrations) protoBundle(id int) (map[string]bool, error) {
+	sequence, err := ms.sequence(0)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := map[string]bool{}
+
+	for _, m := range sequence {
+		if m.ID() >= id {
+			break
+		}
+
+		m.applyProtoBundle(bundle)
+	}
+
+	return bundle, nil
+}
+
+func (m *Migration) applyProtoBundle(bundle map[string]bool) {
+	for _, s := range m.data.GetUpgrade() {
+		if dmlDropProtoBundle.MatchString(s.Sql) {
+			clear(bundle)
+			continue
+		}
+
+		if !isProtoDDL(s.Sql) {
+			continue
+		}
+
+		if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(s.Sql)), "CREATE") {
+			clear(bundle)
+		}
+
+		types, deleted := protoBundleTypes(s.Sql)
+
+		for _, t := range types {
+			bundle[t] = true
+		}
+
+		for _, t := range deleted {
+			delete(bundle, t)
+		}
+	}
+}
+
+// protoBundleSQL returns the statement to create the bundle, or to alter
+// the existing bundle to insert and update the given types, deleting the
+// other bundled types when prune is set.
+func protoBundleSQL(existing map[string]bool, types []string, prune bool) string {
+	types = slices.Sorted(slices.Values(types))
+	types = slices.Compact(types)
+
+	if len(existing) == 0 {
+		return "CREATE PROTO BUNDLE (\n" + protoBundleList(types) + ")"
+	}
+
+	var inserted, updated, deleted []string
+
+	for _, t := range types {
+		if existing[t] {
+			updated = append(updated, t)
+		} else {
+			inserted = append(inserted, t)
+		}
+	}
+
+	for _, t := range slices.Sorted(maps.Keys(existing)) {
+		if prune && !slices.Contains(types, t) {
+			deleted = append(deleted, t)
+		}
+	}
+
+	sql := "ALTER PROTO BUNDLE"
+
+	for _, clause := range []struct {
+		name  string
+		types []string
+	}{
+		{"INSERT", inserted},
+		{"UPDATE", updated},
+		{"DELETE", deleted},
+	} {
+		if len(clause.types) > 0 {
+			sql += "\n" + clause.name + " (\n" + protoBundleList(clause.types) + ")"
+		}
+	}
+
+	return sql
+}
+
+func protoBundleList(types []string) string {
+	var b strings.Builder
+
+	for i, t := range types {
+		b.WriteString("  ")
+		b.WriteString(t)
+		if i < len(types)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// bundleableTypes returns the types in the file descriptor set, excluding
+// the well-known types.
+func bundleableTypes(fileDescriptorSet *descriptorpb.FileDescriptorSet) []string {
+	var types []string
+
+	for _, file := range fileDescriptorSet.File {
+		if strings.HasPrefix(file.GetPackage(), "google.protobuf") {
+			continue
+		}
+
+		types = append(types, protoTypes(file)...)
+	}
+
+	return types
+}
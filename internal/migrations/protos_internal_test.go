@@ -0,0 +1,309 @@
+package migrations
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/silas/jimmy/internal/constants"
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
+)
+
+func TestProtoBundleTypes(t *testing.T) {
+	testCases := []struct {
+		SQL     string
+		Types   []string
+		Deleted []string
+	}{
+		{
+			SQL: "CREATE TABLE test",
+		},
+		{
+			SQL:   "CREATE PROTO BUNDLE (\n  test.v1.User,\n  `test.v1.Status`,\n)",
+			Types: []string{"test.v1.User", "test.v1.Status"},
+		},
+		{
+			SQL:     "ALTER PROTO BUNDLE INSERT (test.v1.Group) UPDATE (test.v1.User) DELETE (test.v1.Status)",
+			Types:   []string{"test.v1.Group", "test.v1.User"},
+			Deleted: []string{"test.v1.Status"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.SQL, func(t *testing.T) {
+			types, deleted := protoBundleTypes(tc.SQL)
+			require.Equal(t, tc.Types, types)
+			require.Equal(t, tc.Deleted, deleted)
+		})
+	}
+}
+
+func TestCompileProto(t *testing.T) {
+	dir := t.TempDir()
+
+	writeProto(t, dir, "test/v1/status.proto", `
+syntax = "proto3";
+package test.v1;
+
+enum Status {
+  UNKNOWN = 0;
+  ACTIVE = 1;
+}
+`)
+
+	writeProto(t, dir, "test/v1/user.proto", `
+syntax = "proto3";
+package test.v1;
+
+import "google/protobuf/timestamp.proto";
+import "test/v1/status.proto";
+
+// A user.
+message User {
+  message Address {
+    string city = 1;
+  }
+
+  string name = 1;
+  Status status = 2;
+  google.protobuf.Timestamp create_time = 3;
+  map<string, string> labels = 4;
+}
+`)
+
+	writeProto(t, dir, "test/v1/group.proto", `
+syntax = "proto3";
+package test.v1;
+
+import "google/protobuf/timestamp.proto";
+import "test/v1/status.proto";
+
+message Group {
+  string name = 1;
+  Member owner = 2;
+}
+
+message Member {
+  string name = 1;
+}
+
+message Unused {
+  google.protobuf.Timestamp create_time = 1;
+  Status status = 2;
+}
+
+enum Visibility {
+  VISIBILITY_UNSPECIFIED = 0;
+}
+`)
+
+	fileDescriptorSet, err := compileProto(
+		context.Background(),
+		[]string{
+			filepath.Join(dir, "test/v1/user.proto"),
+			filepath.Join(dir, "test/v1/group.proto"),
+		},
+		[]string{dir},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"google/protobuf/timestamp.proto",
+		"test/v1/status.proto",
+		"test/v1/user.proto",
+		"test/v1/group.proto",
+	}, fileNames(fileDescriptorSet))
+
+	require.Equal(t, []string{
+		"test.v1.User",
+		"test.v1.User.Address",
+	}, protoTypes(fileDescriptorSet.File[2]))
+
+	filtered, err := filterFileDescriptorSet(fileDescriptorSet, []string{"test.v1.User"})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"google/protobuf/timestamp.proto",
+		"test/v1/status.proto",
+		"test/v1/user.proto",
+	}, fileNames(filtered))
+
+	// unused types are pruned from files, along with their imports
+	filtered, err = filterFileDescriptorSet(fileDescriptorSet, []string{"test.v1.Group"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"test/v1/group.proto"}, fileNames(filtered))
+	require.Equal(t, []string{"test.v1.Group", "test.v1.Member"}, protoTypes(filtered.File[0]))
+	require.Empty(t, filtered.File[0].Dependency)
+	require.Len(t, fileDescriptorSet.File[3].MessageType, 3)
+
+	_, err = protodesc.NewFiles(filtered)
+	require.NoError(t, err)
+
+	filtered, err = filterFileDescriptorSet(fileDescriptorSet, []string{"test.v1.Group", "test.v1.Status"})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"test/v1/status.proto",
+		"test/v1/group.proto",
+	}, fileNames(filtered))
+
+	_, err = filterFileDescriptorSet(fileDescriptorSet, []string{"test.v1.Unknown"})
+	require.EqualError(t, err, `"test.v1.Unknown" type not found in file descriptor set`)
+
+	_, err = compileProto(context.Background(), []string{filepath.Join(dir, "test/v1/user.proto")}, []string{t.TempDir()})
+	require.ErrorContains(t, err, "isn't within an import path")
+}
+
+func writeProto(t *testing.T, dir, name, content string) {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func fileNames(fileDescriptorSet *descriptorpb.FileDescriptorSet) []string {
+	var names []string
+	for _, file := range fileDescriptorSet.File {
+		names = append(names, file.GetName())
+	}
+	return names
+}
+
+func TestProtoBundle(t *testing.T) {
+	ms := New("")
+
+	add := func(id int, sqls ...string) {
+		data := &jimmyv1.Migration{}
+		for _, sql := range sqls {
+			data.Upgrade = append(data.Upgrade, &jimmyv1.Statement{Sql: sql})
+		}
+		ms.migrations[id] = newMigration(ms, id, "", data)
+		ms.latestID = id
+	}
+
+	add(1, "CREATE PROTO BUNDLE (test.v1.User, test.v1.Status)")
+	add(2, "ALTER PROTO BUNDLE INSERT (test.v1.Group) DELETE (test.v1.Status)")
+	add(3, "CREATE TABLE test (id INT64) PRIMARY KEY (id)")
+
+	bundle, err := ms.protoBundle(1)
+	require.NoError(t, err)
+	require.Empty(t, bundle)
+
+	bundle, err = ms.protoBundle(3)
+	require.NoError(t, err)
+	require.Equal(t, map[string]bool{"test.v1.Group": true, "test.v1.User": true}, bundle)
+
+	require.Equal(t,
+		"ALTER PROTO BUNDLE\n"+
+			"INSERT (\n  test.v1.Status\n)\n"+
+			"UPDATE (\n  test.v1.User\n)\n"+
+			"DELETE (\n  test.v1.Group\n)",
+		protoBundleSQL(bundle, []string{"test.v1.User", "test.v1.Status"}, true),
+	)
+
+	require.Equal(t,
+		"ALTER PROTO BUNDLE\n"+
+			"INSERT (\n  test.v1.Status\n)\n"+
+			"UPDATE (\n  test.v1.User\n)",
+		protoBundleSQL(bundle, []string{"test.v1.User", "test.v1.Status"}, false),
+	)
+
+	require.Equal(t,
+		"CREATE PROTO BUNDLE (\n  test.v1.Status,\n  test.v1.User\n)",
+		protoBundleSQL(nil, []string{"test.v1.User", "test.v1.Status", "test.v1.User"}, false),
+	)
+
+	add(4, "DROP PROTO BUNDLE")
+
+	bundle, err = ms.protoBundle(5)
+	require.NoError(t, err)
+	require.Empty(t, bundle)
+}
+
+func TestAddProto_bundle(t *testing.T) {
+	ctx := context.Background()
+
+	dir := t.TempDir()
+
+	writeProto(t, dir, "test/v1/user.proto", `
+syntax = "proto3";
+package test.v1;
+
+message User {
+  string name = 1;
+}
+`)
+
+	writeProto(t, dir, "test/v1/group.proto", `
+syntax = "proto3";
+package test.v1;
+
+message Group {
+  string name = 1;
+}
+`)
+
+	ms := New(filepath.Join(dir, constants.ConfigFile))
+	ms.Config.Path = filepath.Join(dir, constants.MigrationsPath)
+	ms.Config.ProjectId = "test"
+	ms.Config.InstanceId = "test"
+	ms.Config.DatabaseId = "test"
+	require.NoError(t, ms.Init(ctx))
+
+	lastSQL := func(m *Migration) string {
+		upgrade := m.data.GetUpgrade()
+		return upgrade[len(upgrade)-1].GetSql()
+	}
+
+	users, err := ms.Create(ctx, CreateInput{Name: "users", SQL: "CREATE TABLE users (id INT64) PRIMARY KEY (id)"})
+	require.NoError(t, err)
+
+	require.NoError(t, ms.AddProto(ctx, AddProtoInput{
+		ID:          users.ID(),
+		Name:        constants.UpgradeFileDescriptorSet,
+		Paths:       []string{filepath.Join(dir, "test/v1/user.proto")},
+		ImportPaths: []string{dir},
+		Bundle:      true,
+	}))
+	require.Equal(t, "CREATE PROTO BUNDLE (\n  test.v1.User\n)\n", lastSQL(users))
+
+	groups, err := ms.Create(ctx, CreateInput{Name: "groups", SQL: "CREATE TABLE groups (id INT64) PRIMARY KEY (id)"})
+	require.NoError(t, err)
+
+	input := AddProtoInput{
+		ID:          groups.ID(),
+		Name:        constants.UpgradeFileDescriptorSet,
+		Paths:       []string{filepath.Join(dir, "test/v1/group.proto")},
+		ImportPaths: []string{dir},
+		Bundle:      true,
+	}
+
+	// an unrelated file keeps the existing bundle
+	require.NoError(t, ms.AddProto(ctx, input))
+	require.Equal(t, "ALTER PROTO BUNDLE\nINSERT (\n  test.v1.Group\n)\n", lastSQL(groups))
+
+	input.Prune = true
+
+	require.NoError(t, ms.AddProto(ctx, input))
+	require.Equal(t, "ALTER PROTO BUNDLE\nINSERT (\n  test.v1.Group\n)\nDELETE (\n  test.v1.User\n)\n", lastSQL(groups))
+	require.Len(t, groups.data.GetUpgrade(), 2)
+
+	// the bundle is added before statements using its types
+	profiles, err := ms.Create(ctx, CreateInput{
+		Name: "profiles",
+		SQL:  "CREATE TABLE profiles (id INT64, user test.v1.User) PRIMARY KEY (id)",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, ms.AddProto(ctx, AddProtoInput{
+		ID:          profiles.ID(),
+		Name:        constants.UpgradeFileDescriptorSet,
+		Paths:       []string{filepath.Join(dir, "test/v1/user.proto")},
+		ImportPaths: []string{dir},
+		Bundle:      true,
+	}))
+	require.Len(t, profiles.data.GetUpgrade(), 2)
+	require.True(t, isProtoDDL(profiles.data.GetUpgrade()[0].GetSql()))
+	require.Equal(t, "CREATE TABLE profiles (id INT64, user test.v1.User) PRIMARY KEY (id)\n", profiles.data.GetUpgrade()[1].GetSql())
+}
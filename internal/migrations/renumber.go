@@ -0,0 +1,246 @@
+package migrations
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
+)
+
+type RenumberInput struct {
+	// Applied contains the IDs in the migration table, which are never
+	// used as new IDs.
+	//
+	// See AppliedIDs.
+	Applied map[int]bool
+	DryRun  bool
+}
+
+type Renumbered struct {
+	Migration    *Migration
+	FromID       int
+	FromFileName string
+	ID           int
+	FileName     string
+	SquashID     *int
+
+	// Applied is true when the conflicting ID is in the migration table.
+	Applied bool
+}
+
+type RenumberResult struct {
+	// Renumbered contains the moved migration files.
+	Renumbered []*Renumbered
+
+	// Updated contains the migrations that keep their ID and file name but
+	// have their squash ID rewritten.
+	Updated []*Renumbered
+
+	// Conflicts contains the IDs used by more than one migration file.
+	Conflicts []int
+
+	// Unapplied contains the IDs of the migrations, after renumbering, that
+	// aren't in the migration table. It's only set when the applied IDs are
+	// known.
+	Unapplied []int
+}
+
+// Renumber moves conflicting migration files to the next free IDs, keeping
+// the file that sorts first for each conflicting ID, and rewrites squash
+// IDs referencing a moved ID.
+func (ms *Migrations) Renumber(input RenumberInput) (*RenumberResult, error) {
+	result := &RenumberResult{}
+
+	var moves []*Migration
+	keep := map[int]*Migration{}
+
+	for _, c := range ms.conflicts {
+		m := keep[c.ID()]
+		if m == nil {
+			m = ms.migrations[c.ID()]
+		}
+
+		// keep the file that sorts first
+		if c.FileName() < m.FileName() {
+			m, c = c, m
+		}
+
+		keep[m.ID()] = m
+		moves = append(moves, c)
+
+		if !slices.Contains(result.Conflicts, c.ID()) {
+			result.Conflicts = append(result.Conflicts, c.ID())
+		}
+	}
+
+	slices.Sort(result.Conflicts)
+
+	slices.SortFunc(moves, func(a, b *Migration) int {
+		if a.ID() != b.ID() {
+			return a.ID() - b.ID()
+		}
+		return strings.Compare(a.FileName(), b.FileName())
+	})
+
+	nextID := ms.nextID() - 1
+	if ms.Config.GetIdScheme() == jimmyv1.IdScheme_SEQUENTIAL {
+		for id := range input.Applied {
+			nextID = max(nextID, id)
+		}
+	}
+
+	ids := map[int]int{}
+
+	for _, m := range moves {
+		nextID++
+		for {
+			_, applied := input.Applied[nextID]
+			if !applied && ms.migrations[nextID] == nil {
+				break
+			}
+			nextID++
+		}
+
+		if _, found := ids[m.ID()]; !found {
+			ids[m.ID()] = nextID
+		}
+
+		_, applied := input.Applied[m.ID()]
+
+		result.Renumbered = append(result.Renumbered, &Renumbered{
+			Migration:    m,
+			FromID:       m.ID(),
+			FromFileName: m.FileName(),
+			ID:           nextID,
+			FileName:     ms.fileName(nextID, m.Slug()),
+			Applied:      applied,
+		})
+	}
+
+	for _, r := range result.Renumbered {
+		if squashID, found := r.Migration.SquashID(); found {
+			if id, found := ids[squashID]; found {
+				r.SquashID = Ref(id)
+			}
+		}
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(ms.migrations)) {
+		m := keep[id]
+		if m == nil {
+			m = ms.migrations[id]
+		}
+
+		squashID, found := m.SquashID()
+		if !found {
+			continue
+		}
+
+		if newID, found := ids[squashID]; found {
+			result.Updated = append(result.Updated, &Renumbered{
+				Migration:    m,
+				FromID:       m.ID(),
+				FromFileName: m.FileName(),
+				ID:           m.ID(),
+				FileName:     m.FileName(),
+				SquashID:     Ref(newID),
+				Applied:      input.Applied[m.ID()],
+			})
+		}
+	}
+
+	if input.Applied != nil {
+		unapplied := map[int]bool{}
+
+		for id := range ms.migrations {
+			unapplied[id] = !input.Applied[id]
+		}
+
+		for _, r := range result.Renumbered {
+			unapplied[r.ID] = true
+		}
+
+		for _, id := range slices.Sorted(maps.Keys(unapplied)) {
+			if unapplied[id] {
+				result.Unapplied = append(result.Unapplied, id)
+			}
+		}
+	}
+
+	if input.DryRun {
+		return result, nil
+	}
+
+	for _, m := range keep {
+		ms.migrations[m.ID()] = m
+	}
+
+	ms.conflicts = nil
+
+	for _, r := range result.Renumbered {
+		err := ms.renumber(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, r := range result.Updated {
+		r.Migration.data.SquashId = Ref(int64(*r.SquashID))
+
+		err := Marshal(r.Migration.Path(), r.Migration.data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ms.squash = map[int]int{}
+	for _, m := range ms.migrations {
+		ms.setMigration(m)
+	}
+
+	return result, nil
+}
+
+func (ms *Migrations) renumber(r *Renumbered) error {
+	oldPath := r.Migration.Path()
+	newPath := filepath.Join(ms.Config.Path, r.FileName)
+
+	_, err := os.Stat(newPath)
+	if err == nil {
+		return fmt.Errorf("%q already exists", newPath)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	if r.SquashID != nil {
+		r.Migration.data.SquashId = Ref(int64(*r.SquashID))
+
+		err = Marshal(newPath, r.Migration.data)
+		if err != nil {
+			return err
+		}
+
+		err = os.Remove(oldPath)
+		if err != nil {
+			return err
+		}
+	} else {
+		err = os.Rename(oldPath, newPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	r.Migration.id = r.ID
+	r.Migration.fileName = r.FileName
+
+	ms.migrations[r.ID] = r.Migration
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package migrations
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenumber(t *testing.T) {
+	dir := t.TempDir()
+
+	write := func(name, content string) {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+		require.NoError(t, err)
+	}
+
+	write(".jimmy.yaml", "path: "+dir+"\nproject_id: test\ninstance_id: test\ndatabase_id: test\ntable: migrations\n")
+	write("00001_init.yaml", "upgrade:\n  - sql: SELECT 1\n")
+	write("00002_a.yaml", "upgrade:\n  - sql: SELECT 1\n")
+	write("00002_b.yaml", "upgrade:\n  - sql: SELECT 1\n")
+	write("00003_c.yaml", "upgrade:\n  - sql: SELECT 1\n")
+	write("00003_d.yaml", "upgrade:\n  - sql: SELECT 1\nsquash_id: 2\n")
+
+	load := func() *Migrations {
+		ms := New(filepath.Join(dir, ".jimmy.yaml"))
+		require.NoError(t, ms.LoadAll(context.Background()))
+		return ms
+	}
+
+	ms := load()
+	require.Error(t, ms.Validate())
+
+	result, err := ms.Renumber(RenumberInput{
+		Applied: map[int]bool{1: true, 2: true, 5: true},
+		DryRun:  true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int{2, 3}, result.Conflicts)
+	require.Equal(t, []int{3, 6, 7}, result.Unapplied)
+	require.Empty(t, result.Updated)
+
+	renumbered := result.Renumbered
+	require.Len(t, renumbered, 2)
+	require.Equal(t, "00002_b.yaml", renumbered[0].FromFileName)
+	require.Equal(t, "00006_b.yaml", renumbered[0].FileName)
+	require.True(t, renumbered[0].Applied)
+	require.Nil(t, renumbered[0].SquashID)
+	require.Equal(t, "00003_d.yaml", renumbered[1].FromFileName)
+	require.Equal(t, "00007_d.yaml", renumbered[1].FileName)
+	require.False(t, renumbered[1].Applied)
+	require.Equal(t, Ref(6), renumbered[1].SquashID)
+
+	_, err = os.Stat(filepath.Join(dir, "00002_b.yaml"))
+	require.NoError(t, err)
+
+	_, err = ms.Renumber(RenumberInput{
+		Applied: map[int]bool{1: true, 2: true, 5: true},
+	})
+	require.NoError(t, err)
+
+	ms.Config.AllowIdGaps = true
+	require.NoError(t, ms.Validate())
+
+	ms = load()
+	ms.Config.AllowIdGaps = true
+	require.NoError(t, ms.Validate())
+
+	m, err := ms.Get(7)
+	require.NoError(t, err)
+	require.Equal(t, "00007_d.yaml", m.FileName())
+
+	squashID, found := m.SquashID()
+	require.True(t, found)
+	require.Equal(t, 6, squashID)
+}
+
+func TestRenumber_squash(t *testing.T) {
+	dir := t.TempDir()
+
+	write := func(name, content string) {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+		require.NoError(t, err)
+	}
+
+	write(".jimmy.yaml", "path: "+dir+"\nproject_id: test\ninstance_id: test\ndatabase_id: test\ntable: migrations\n")
+	write("00001_init.yaml", "upgrade:\n  - sql: SELECT 1\n")
+	write("00002_a.yaml", "upgrade:\n  - sql: SELECT 1\n")
+	write("00002_b.yaml", "upgrade:\n  - sql: SELECT 1\n")
+	write("00003_squash.yaml", "upgrade:\n  - sql: SELECT 1\nsquash_id: 2\n")
+
+	ms := New(filepath.Join(dir, ".jimmy.yaml"))
+	require.NoError(t, ms.LoadAll(context.Background()))
+
+	// unapplied migrations aren't known without the migration table
+	result, err := ms.Renumber(RenumberInput{DryRun: true})
+	require.NoError(t, err)
+	require.Nil(t, result.Unapplied)
+
+	result, err = ms.Renumber(RenumberInput{Applied: map[int]bool{1: true}})
+	require.NoError(t, err)
+	require.Equal(t, []int{2}, result.Conflicts)
+	require.Equal(t, []int{2, 3, 4}, result.Unapplied)
+	require.Len(t, result.Renumbered, 1)
+	require.Equal(t, "00004_b.yaml", result.Renumbered[0].FileName)
+
+	// migrations keeping their ID have references to moved IDs rewritten
+	require.Len(t, result.Updated, 1)
+	require.Equal(t, "00003_squash.yaml", result.Updated[0].FileName)
+	require.Equal(t, Ref(4), result.Updated[0].SquashID)
+
+	ms = New(filepath.Join(dir, ".jimmy.yaml"))
+	require.NoError(t, ms.LoadAll(context.Background()))
+
+	m, err := ms.Get(3)
+	require.NoError(t, err)
+
+	squashID, found := m.SquashID()
+	require.True(t, found)
+	require.Equal(t, 4, squashID)
+}
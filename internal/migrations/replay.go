@@ -0,0 +1,59 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
+)
+
+// replay runs all migrations against a temporary emulator database and
+// calls fn with the replayed migrations before dropping the database.
+func (ms *Migrations) replay(
+	ctx context.Context,
+	fn func(ctx context.Context, rms *Migrations) error,
+) (err error) {
+	if !ms.emulator {
+		return errors.New("replaying migrations requires the emulator")
+	}
+
+	rms := &Migrations{
+		Path:   ms.Path,
+		Config: proto.Clone(ms.Config).(*jimmyv1.Config),
+
+		emulator:   ms.emulator,
+		migrations: ms.migrations,
+		squash:     ms.squash,
+		latestID:   ms.latestID,
+	}
+	defer rms.Close()
+
+	rms.Config.DatabaseId = fmt.Sprintf("replay_%d", time.Now().UnixNano())
+
+	defer func() {
+		if rms.database != nil {
+			rms.database.Close()
+			rms.database = nil
+		}
+
+		if !rms.databaseEnsured {
+			return
+		}
+
+		dropErr := rms.executor().DropDatabase(ctx)
+		if dropErr != nil && err == nil {
+			err = fmt.Errorf("failed to drop replay database: %w", dropErr)
+		}
+	}()
+
+	err = rms.Upgrade(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to replay migrations: %w", err)
+	}
+
+	return fn(ctx, rms)
+}
@@ -0,0 +1,132 @@
+package migrations
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"maps"
+	"path/filepath"
+	"slices"
+
+	"cloud.google.com/go/spanner"
+	"gopkg.in/yaml.v3"
+)
+
+// readRows reads the rows of a CSV, newline-delimited JSON, JSON or YAML
+// file.
+func readRows(path string, b []byte) ([]map[string]any, error) {
+	var rows []map[string]any
+
+	switch filepath.Ext(path) {
+	case ".csv":
+		r := csv.NewReader(bytes.NewReader(b))
+
+		header, err := r.Read()
+		if err == io.EOF {
+			return nil, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read %q: %w", path, err)
+		}
+
+		for {
+			record, err := r.Read()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("failed to read %q: %w", path, err)
+			}
+
+			row := map[string]any{}
+			for i, name := range header {
+				row[name] = record[i]
+			}
+
+			rows = append(rows, row)
+		}
+
+		return rows, nil
+	case ".ndjson", ".jsonl":
+		scanner := bufio.NewScanner(bytes.NewReader(b))
+		scanner.Buffer(nil, len(b)+1)
+
+		for line := 1; scanner.Scan(); line++ {
+			if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+				continue
+			}
+
+			var row map[string]any
+
+			err := yaml.Unmarshal(scanner.Bytes(), &row)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %q line %d: %w", path, line, err)
+			}
+
+			rows = append(rows, row)
+		}
+
+		err := scanner.Err()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %q: %w", path, err)
+		}
+
+		return rows, nil
+	}
+
+	err := yaml.Unmarshal(b, &rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %q: %w", path, err)
+	}
+
+	return rows, nil
+}
+
+// rowValues returns the sorted column names and coerced values for a row.
+func rowValues(columns map[string]string, row map[string]any) ([]string, []any, error) {
+	var names []string
+	var values []any
+
+	for _, name := range slices.Sorted(maps.Keys(row)) {
+		spannerType, found := columns[name]
+		if !found {
+			return nil, nil, fmt.Errorf("column %q not found", name)
+		}
+
+		value, err := coerceValue(spannerType, row[name])
+		if err != nil {
+			return nil, nil, fmt.Errorf("column %q: %w", name, err)
+		}
+
+		names = append(names, name)
+		values = append(values, value)
+	}
+
+	return names, values, nil
+}
+
+// writeRows writes rows to a table using insert or update mutations,
+// committing them in batches.
+func (ms *Migrations) writeRows(
+	ctx context.Context,
+	table string,
+	columns map[string]string,
+	rows []map[string]any,
+) error {
+	batch := &mutationBatch{ms: ms}
+
+	for i, row := range rows {
+		names, values, err := rowValues(columns, row)
+		if err != nil {
+			return fmt.Errorf("row %d: %w", i+1, err)
+		}
+
+		err = batch.add(ctx, spanner.InsertOrUpdate(table, names, values), values)
+		if err != nil {
+			return err
+		}
+	}
+
+	return batch.flush(ctx)
+}
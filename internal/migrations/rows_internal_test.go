@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadRows(t *testing.T) {
+	rows, err := readRows("users.csv", []byte("id,name\n1,one\n2,\n"))
+	require.NoError(t, err)
+	require.Equal(t, []map[string]any{
+		{"id": "1", "name": "one"},
+		{"id": "2", "name": ""},
+	}, rows)
+
+	rows, err = readRows("users.yaml", []byte("- id: 1\n  name: one\n- id: 2\n"))
+	require.NoError(t, err)
+	require.Equal(t, []map[string]any{
+		{"id": 1, "name": "one"},
+		{"id": 2},
+	}, rows)
+
+	rows, err = readRows("users.json", []byte(`[{"id": 1, "tags": ["a"]}]`))
+	require.NoError(t, err)
+	require.Equal(t, []map[string]any{
+		{"id": 1, "tags": []any{"a"}},
+	}, rows)
+
+	rows, err = readRows("users.ndjson", []byte("{\"id\": 1, \"name\": \"one\"}\n\n{\"id\": 2}\n"))
+	require.NoError(t, err)
+	require.Equal(t, []map[string]any{
+		{"id": 1, "name": "one"},
+		{"id": 2},
+	}, rows)
+
+	_, err = readRows("users.jsonl", []byte("{\"id\": 1}\n{\"id\": \n"))
+	require.ErrorContains(t, err, "line 2")
+
+	names, values, err := rowValues(map[string]string{"id": "INT64"}, map[string]any{"id": 1, "tags": []any{"a"}})
+	require.Error(t, err)
+	require.Nil(t, names)
+	require.Nil(t, values)
+}
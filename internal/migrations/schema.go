@@ -0,0 +1,343 @@
+package migrations
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/silas/jimmy/internal/constants"
+)
+
+var (
+	ddlCreate = regexp.MustCompile(
+		"^(?is)\\s*CREATE\\s+(?:OR\\s+REPLACE\\s+)?(?:UNIQUE\\s+)?(?:NULL_FILTERED\\s+)?" +
+			"(PROTO\\s+BUNDLE|SEQUENCE|TABLE|(?:SEARCH\\s+|VECTOR\\s+)?INDEX|VIEW|CHANGE\\s+STREAM|MODEL|ROLE)" +
+			"\\s*(?:IF\\s+NOT\\s+EXISTS\\s+)?`?([\\w.]*)`?",
+	)
+	ddlAlterTable  = regexp.MustCompile("^(?is)\\s*ALTER\\s+TABLE\\s+`?([\\w.]+)`?")
+	ddlInterleave  = regexp.MustCompile("(?is)INTERLEAVE\\s+IN\\s+PARENT\\s+`?([\\w.]+)`?")
+	ddlReferences  = regexp.MustCompile("(?is)REFERENCES\\s+`?([\\w.]+)`?")
+	ddlWhitespaces = regexp.MustCompile(`\s+`)
+)
+
+type Schema struct {
+	Statements        []string
+	FileDescriptorSet *descriptorpb.FileDescriptorSet
+}
+
+func (s *Schema) SQL() string {
+	var b strings.Builder
+
+	for i, sql := range s.Statements {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+
+		b.WriteString(strings.TrimSpace(sql))
+		b.WriteString(";\n")
+	}
+
+	return b.String()
+}
+
+func (s *Schema) ProtoDescriptors() ([]byte, error) {
+	if s.FileDescriptorSet == nil || len(s.FileDescriptorSet.File) == 0 {
+		return nil, nil
+	}
+
+	return proto.MarshalOptions{Deterministic: true}.Marshal(s.FileDescriptorSet)
+}
+
+func (ms *Migrations) SchemaPath() string {
+	if ms.Config.Schema != "" {
+		return ms.Config.Schema
+	}
+	return constants.SchemaFile
+}
+
+func (ms *Migrations) SchemaProtoPath() string {
+	path := ms.SchemaPath()
+	return strings.TrimSuffix(path, filepath.Ext(path)) + constants.SchemaProtoExt
+}
+
+func (ms *Migrations) Schema(ctx context.Context) (*Schema, error) {
+	schema, err := ms.getSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	schema.Statements = sortDDL(schema.Statements)
+
+	if schema.FileDescriptorSet != nil {
+		for _, file := range schema.FileDescriptorSet.File {
+			file.SourceCodeInfo = nil
+		}
+
+		slices.SortFunc(schema.FileDescriptorSet.File, func(a, b *descriptorpb.FileDescriptorProto) int {
+			return strings.Compare(a.GetName(), b.GetName())
+		})
+	}
+
+	return schema, nil
+}
+
+func (ms *Migrations) DumpSchema(ctx context.Context) error {
+	schema, err := ms.Schema(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(ms.SchemaPath(), []byte(schema.SQL()), 0644)
+	if err != nil {
+		return err
+	}
+
+	b, err := schema.ProtoDescriptors()
+	if err != nil {
+		return fmt.Errorf("failed to marshal file descriptor set: %w", err)
+	}
+
+	if len(b) == 0 {
+		err = os.Remove(ms.SchemaProtoPath())
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+
+		return nil
+	}
+
+	return os.WriteFile(ms.SchemaProtoPath(), b, 0644)
+}
+
+func (ms *Migrations) CheckSchema(ctx context.Context) error {
+	path := ms.SchemaPath()
+
+	err := checkFile(path, "schema")
+	if err != nil {
+		return err
+	}
+
+	current, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	currentProto, err := os.ReadFile(ms.SchemaProtoPath())
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	var schema *Schema
+
+	err = ms.replay(ctx, func(ctx context.Context, rms *Migrations) error {
+		var err error
+		schema, err = rms.Schema(ctx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if line := diffLine(string(current), schema.SQL()); line > 0 {
+		return fmt.Errorf("%q doesn't match migrations at line %d", path, line)
+	}
+
+	b, err := schema.ProtoDescriptors()
+	if err != nil {
+		return fmt.Errorf("failed to marshal file descriptor set: %w", err)
+	}
+
+	if !bytes.Equal(currentProto, b) {
+		return fmt.Errorf("%q doesn't match migrations", ms.SchemaProtoPath())
+	}
+
+	return nil
+}
+
+func (ms *Migrations) getSchema(ctx context.Context) (*Schema, error) {
+	err := ms.ensureAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statements, protoDescriptors, err := ms.executor().GetDDL(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &Schema{}
+
+	for _, sql := range statements {
+		if ms.isInternalDDL(sql) {
+			continue
+		}
+
+		schema.Statements = append(schema.Statements, sql)
+	}
+
+	if len(protoDescriptors) > 0 {
+		schema.FileDescriptorSet = &descriptorpb.FileDescriptorSet{}
+
+		err = proto.Unmarshal(protoDescriptors, schema.FileDescriptorSet)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal file descriptor set: %w", err)
+		}
+	}
+
+	return schema, nil
+}
+
+func (ms *Migrations) isInternalDDL(sql string) bool {
+	for _, table := range []string{ms.Config.Table, ms.seedsTable()} {
+		if strings.Contains(sql, fmt.Sprintf("CREATE TABLE %s (", table)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type ddlObject struct {
+	sql  string
+	rank int
+	name string
+	deps []string
+}
+
+// sortDDL orders statements by object type and then by name, while
+// keeping interleaved and referenced tables after their dependencies.
+func sortDDL(statements []string) []string {
+	var objects []*ddlObject
+
+	for _, sql := range statements {
+		o := &ddlObject{sql: sql, rank: 10}
+
+		if match := ddlCreate.FindStringSubmatch(sql); match != nil {
+			o.name = match[2]
+
+			switch kind := strings.ToUpper(ddlWhitespaces.ReplaceAllString(match[1], " ")); kind {
+			case "PROTO BUNDLE":
+				o.rank = 0
+			case "SEQUENCE":
+				o.rank = 1
+			case "TABLE":
+				o.rank = 2
+
+				for _, re := range []*regexp.Regexp{ddlInterleave, ddlReferences} {
+					for _, dep := range re.FindAllStringSubmatch(sql, -1) {
+						if dep[1] != o.name {
+							o.deps = append(o.deps, dep[1])
+						}
+					}
+				}
+			case "VIEW":
+				o.rank = 5
+			case "CHANGE STREAM":
+				o.rank = 6
+			case "MODEL":
+				o.rank = 7
+			case "ROLE":
+				o.rank = 8
+			default:
+				o.rank = 3
+			}
+		} else if match := ddlAlterTable.FindStringSubmatch(sql); match != nil {
+			o.rank = 4
+			o.name = match[1]
+		}
+
+		objects = append(objects, o)
+	}
+
+	viewNames := map[*ddlObject]*regexp.Regexp{}
+
+	for _, o := range objects {
+		if o.rank == 5 {
+			viewNames[o] = regexp.MustCompile(`\b` + regexp.QuoteMeta(o.name) + `\b`)
+		}
+	}
+
+	for o := range viewNames {
+		for other, name := range viewNames {
+			if other != o && name.MatchString(o.sql) {
+				o.deps = append(o.deps, other.name)
+			}
+		}
+	}
+
+	slices.SortStableFunc(objects, func(a, b *ddlObject) int {
+		if a.rank != b.rank {
+			return a.rank - b.rank
+		}
+		if a.rank == 10 {
+			return 0
+		}
+		if c := strings.Compare(a.name, b.name); c != 0 {
+			return c
+		}
+		return strings.Compare(a.sql, b.sql)
+	})
+
+	sorted := make([]string, 0, len(objects))
+	added := map[string]bool{}
+
+	for len(objects) > 0 {
+		idx := 0
+
+		for i, o := range objects {
+			if o.rank != objects[0].rank {
+				break
+			}
+
+			ready := true
+			for _, dep := range o.deps {
+				if !added[dep] && slices.ContainsFunc(objects, func(other *ddlObject) bool {
+					return other.rank == o.rank && other.name == dep
+				}) {
+					ready = false
+					break
+				}
+			}
+
+			if ready {
+				idx = i
+				break
+			}
+		}
+
+		o := objects[idx]
+		objects = slices.Delete(objects, idx, idx+1)
+
+		sorted = append(sorted, o.sql)
+		added[o.name] = true
+	}
+
+	return sorted
+}
+
+func diffLine(a, b string) int {
+	if a == b {
+		return 0
+	}
+
+	aLines := strings.Split(a, "\n")
+	bLines := strings.Split(b, "\n")
+
+	for i := range min(len(aLines), len(bLines)) {
+		if aLines[i] != bLines[i] {
+			return i + 1
+		}
+	}
+
+	return min(len(aLines), len(bLines)) + 1
+}
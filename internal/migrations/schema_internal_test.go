@@ -0,0 +1,103 @@
+package migrations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortDDL(t *testing.T) {
+	testCases := []struct {
+		Name       string
+		Statements []string
+		Expected   []string
+	}{
+		{
+			Name: "Types",
+			Statements: []string{
+				"CREATE CHANGE STREAM everything FOR ALL",
+				"CREATE VIEW v SQL SECURITY INVOKER AS SELECT a.id FROM a",
+				"CREATE INDEX idx_a ON a (name)",
+				"CREATE TABLE a (\n  id STRING(MAX) NOT NULL,\n) PRIMARY KEY(id)",
+				"CREATE PROTO BUNDLE (\n  test.Message,\n)",
+			},
+			Expected: []string{
+				"CREATE PROTO BUNDLE (\n  test.Message,\n)",
+				"CREATE TABLE a (\n  id STRING(MAX) NOT NULL,\n) PRIMARY KEY(id)",
+				"CREATE INDEX idx_a ON a (name)",
+				"CREATE VIEW v SQL SECURITY INVOKER AS SELECT a.id FROM a",
+				"CREATE CHANGE STREAM everything FOR ALL",
+			},
+		},
+		{
+			Name: "Names",
+			Statements: []string{
+				"CREATE TABLE c (id INT64) PRIMARY KEY(id)",
+				"CREATE UNIQUE NULL_FILTERED INDEX idx_b ON b (id)",
+				"CREATE TABLE b (id INT64) PRIMARY KEY(id)",
+				"CREATE INDEX idx_a ON a (id)",
+				"CREATE TABLE a (id INT64) PRIMARY KEY(id)",
+			},
+			Expected: []string{
+				"CREATE TABLE a (id INT64) PRIMARY KEY(id)",
+				"CREATE TABLE b (id INT64) PRIMARY KEY(id)",
+				"CREATE TABLE c (id INT64) PRIMARY KEY(id)",
+				"CREATE INDEX idx_a ON a (id)",
+				"CREATE UNIQUE NULL_FILTERED INDEX idx_b ON b (id)",
+			},
+		},
+		{
+			Name: "Dependencies",
+			Statements: []string{
+				"CREATE TABLE a (id INT64, b_id INT64, CONSTRAINT fk_b FOREIGN KEY (b_id) REFERENCES c (id)) PRIMARY KEY(id)",
+				"CREATE TABLE b (id INT64) PRIMARY KEY(id), INTERLEAVE IN PARENT c ON DELETE CASCADE",
+				"CREATE TABLE c (id INT64) PRIMARY KEY(id)",
+				"CREATE VIEW v1 SQL SECURITY INVOKER AS SELECT v2.id FROM v2",
+				"CREATE VIEW v2 SQL SECURITY INVOKER AS SELECT c.id FROM c",
+			},
+			Expected: []string{
+				"CREATE TABLE c (id INT64) PRIMARY KEY(id)",
+				"CREATE TABLE a (id INT64, b_id INT64, CONSTRAINT fk_b FOREIGN KEY (b_id) REFERENCES c (id)) PRIMARY KEY(id)",
+				"CREATE TABLE b (id INT64) PRIMARY KEY(id), INTERLEAVE IN PARENT c ON DELETE CASCADE",
+				"CREATE VIEW v2 SQL SECURITY INVOKER AS SELECT c.id FROM c",
+				"CREATE VIEW v1 SQL SECURITY INVOKER AS SELECT v2.id FROM v2",
+			},
+		},
+		{
+			Name: "Other",
+			Statements: []string{
+				"GRANT SELECT ON TABLE a TO ROLE reader",
+				"CREATE ROLE reader",
+				"ALTER TABLE a ADD CONSTRAINT fk_b FOREIGN KEY (b_id) REFERENCES b (id)",
+				"CREATE TABLE a (id INT64) PRIMARY KEY(id)",
+			},
+			Expected: []string{
+				"CREATE TABLE a (id INT64) PRIMARY KEY(id)",
+				"ALTER TABLE a ADD CONSTRAINT fk_b FOREIGN KEY (b_id) REFERENCES b (id)",
+				"CREATE ROLE reader",
+				"GRANT SELECT ON TABLE a TO ROLE reader",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			require.Equal(t, tc.Expected, sortDDL(tc.Statements))
+		})
+	}
+}
+
+func TestSchemaSQL(t *testing.T) {
+	schema := &Schema{
+		Statements: []string{
+			"CREATE TABLE a (id INT64) PRIMARY KEY(id)\n",
+			"CREATE INDEX idx_a ON a (id)",
+		},
+	}
+
+	require.Equal(
+		t,
+		"CREATE TABLE a (id INT64) PRIMARY KEY(id);\n\nCREATE INDEX idx_a ON a (id);\n",
+		schema.SQL(),
+	)
+}
@@ -0,0 +1,51 @@
+package migrations_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/silas/jimmy/internal/migrations"
+)
+
+func TestMigrations_Schema(t *testing.T) {
+	h := helper(t)
+
+	h.Migrations.Config.Schema = h.Path + "/schema.sql"
+
+	err := h.Migrations.Init(h.Ctx)
+	require.NoError(t, err)
+
+	_, err = h.Migrations.Create(h.Ctx, migrations.CreateInput{
+		Name:       "init",
+		TemplateID: "create-table",
+	})
+	require.NoError(t, err)
+
+	err = h.Migrations.Upgrade(h.Ctx)
+	require.NoError(t, err)
+
+	err = h.Migrations.DumpSchema(h.Ctx)
+	require.NoError(t, err)
+
+	b, err := os.ReadFile(h.Migrations.SchemaPath())
+	require.NoError(t, err)
+	require.Contains(t, string(b), "CREATE TABLE test (")
+	require.NotContains(t, string(b), "CREATE TABLE migrations (")
+
+	_, err = os.Stat(h.Migrations.SchemaProtoPath())
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	err = h.Migrations.CheckSchema(h.Ctx)
+	require.NoError(t, err)
+
+	_, err = h.Migrations.Create(h.Ctx, migrations.CreateInput{
+		Name:       "add-column",
+		TemplateID: "add-column",
+	})
+	require.NoError(t, err)
+
+	err = h.Migrations.CheckSchema(h.Ctx)
+	require.ErrorContains(t, err, "doesn't match migrations")
+}
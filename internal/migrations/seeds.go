@@ -0,0 +1,206 @@
+package migrations
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"cloud.google.com/go/spanner"
+
+	"github.com/silas/jimmy/internal/constants"
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
+)
+
+var seedOrderPrefix = regexp.MustCompile(`^[0-9]+_`)
+
+type SeedInput struct {
+	// Env is the environment to seed, defaulting to the detected
+	// environment.
+	Env jimmyv1.Environment
+}
+
+type Seed struct {
+	Path    string
+	Table   string
+	Rows    int
+	Skipped bool
+}
+
+func (ms *Migrations) SeedsPath() string {
+	if ms.Config.Seeds != "" {
+		return ms.Config.Seeds
+	}
+	return constants.SeedsPath
+}
+
+func (ms *Migrations) seedsTable() string {
+	return ms.Config.Table + constants.SeedsTableExt
+}
+
+// Seed writes the seed files for the environment, skipping files that
+// haven't changed since they were last written.
+func (ms *Migrations) Seed(ctx context.Context, input SeedInput) ([]*Seed, error) {
+	env := input.Env
+	if env == jimmyv1.Environment_ALL {
+		env = ms.environment()
+	}
+
+	paths, err := ms.seedFiles(env)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ms.ensureAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ms.ensureTableDDL(ctx, ms.seedsTable(), constants.CreateSeedsTable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to ensure seeds table: %w", err)
+	}
+
+	checksums, err := ms.executor().SeedChecksums(ctx, ms.seedsTable())
+	if err != nil {
+		return nil, err
+	}
+
+	tables := map[string]map[string]string{}
+
+	var seeds []*Seed
+
+	for _, path := range paths {
+		rel, err := filepath.Rel(ms.SeedsPath(), path)
+		if err != nil {
+			return nil, err
+		}
+
+		seed := &Seed{
+			Path:  filepath.ToSlash(rel),
+			Table: seedTable(path),
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		sum := sha256.Sum256(b)
+		checksum := hex.EncodeToString(sum[:])
+
+		if checksums[seed.Path] == checksum {
+			seed.Skipped = true
+			seeds = append(seeds, seed)
+			continue
+		}
+
+		rows, err := readRows(path, b)
+		if err != nil {
+			return nil, err
+		}
+
+		columns := tables[seed.Table]
+		if columns == nil {
+			columns, err = ms.columnTypes(ctx, seed.Table)
+			if err != nil {
+				return nil, fmt.Errorf("%q: %w", seed.Path, err)
+			}
+
+			tables[seed.Table] = columns
+		}
+
+		err = ms.writeRows(ctx, seed.Table, columns, rows)
+		if err != nil {
+			return nil, fmt.Errorf("%q %w", seed.Path, err)
+		}
+
+		err = ms.executor().Apply(ctx, []*spanner.Mutation{
+			spanner.InsertOrUpdate(
+				ms.seedsTable(),
+				[]string{"path", "checksum", "update_time"},
+				[]any{seed.Path, checksum, spanner.CommitTimestamp},
+			),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		seed.Rows = len(rows)
+		seeds = append(seeds, seed)
+	}
+
+	return seeds, nil
+}
+
+// seedFiles returns the seed files for all environments followed by the
+// seed files for the given environment and the active user-defined
+// environment.
+func (ms *Migrations) seedFiles(env jimmyv1.Environment) ([]string, error) {
+	dir := ms.SeedsPath()
+
+	paths, err := seedDirFiles(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%q seeds directory not found", dir)
+		}
+		return nil, err
+	}
+
+	envDirs := []string{strings.ToLower(env.String())}
+	if ms.Env != "" {
+		envDirs = append(envDirs, ms.Env)
+	}
+
+	for _, envDir := range envDirs {
+		envPaths, err := seedDirFiles(filepath.Join(dir, envDir))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+
+		paths = append(paths, envPaths...)
+	}
+
+	return paths, nil
+}
+
+func seedDirFiles(dir string) ([]string, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+
+	for _, file := range files {
+		if !file.Type().IsRegular() || strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+
+		switch filepath.Ext(file.Name()) {
+		case ".yaml", ".yml", ".json", ".csv":
+			paths = append(paths, filepath.Join(dir, file.Name()))
+		default:
+			return nil, fmt.Errorf("%q isn't a YAML, JSON or CSV seed file", filepath.Join(dir, file.Name()))
+		}
+	}
+
+	slices.Sort(paths)
+
+	return paths, nil
+}
+
+// seedTable returns the table for a seed file, which is the file name
+// without the extension or an optional numeric ordering prefix.
+func seedTable(path string) string {
+	name := filepath.Base(path)
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	return seedOrderPrefix.ReplaceAllString(name, "")
+}
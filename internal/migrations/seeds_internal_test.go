@@ -0,0 +1,52 @@
+package migrations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
+)
+
+func TestSeedFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, name := range []string{
+		"02_posts.csv",
+		"01_users.yaml",
+		"emulator/users.json",
+		"google_cloud/users.yaml",
+		".hidden",
+	} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, nil, 0644))
+	}
+
+	ms := New("")
+	ms.Config.Seeds = dir
+
+	paths, err := ms.seedFiles(jimmyv1.Environment_EMULATOR)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(dir, "01_users.yaml"),
+		filepath.Join(dir, "02_posts.csv"),
+		filepath.Join(dir, "emulator/users.json"),
+	}, paths)
+
+	require.Equal(t, "users", seedTable(paths[0]))
+	require.Equal(t, "posts", seedTable(paths[1]))
+	require.Equal(t, "users", seedTable(paths[2]))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), nil, 0644))
+
+	_, err = ms.seedFiles(jimmyv1.Environment_EMULATOR)
+	require.ErrorContains(t, err, "isn't a YAML, JSON or CSV seed file")
+
+	ms.Config.Seeds = filepath.Join(dir, "missing")
+
+	_, err = ms.seedFiles(jimmyv1.Environment_EMULATOR)
+	require.ErrorContains(t, err, "seeds directory not found")
+}
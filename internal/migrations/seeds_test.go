@@ -0,0 +1,126 @@
+package migrations_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path"
+	"testing"
+
+	"cloud.google.com/go/spanner"
+	"github.com/stretchr/testify/require"
+
+	"github.com/silas/jimmy/internal/migrations"
+	"github.com/silas/jimmy/internal/migrations/migrationstest"
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
+)
+
+func TestMigrations_Seed(t *testing.T) {
+	h := helper(t)
+
+	err := h.Migrations.Init(h.Ctx)
+	require.NoError(t, err)
+
+	_, err = h.Migrations.Create(h.Ctx, migrations.CreateInput{
+		Name:       "init",
+		TemplateID: "create-table",
+	})
+	require.NoError(t, err)
+
+	err = h.Migrations.Upgrade(h.Ctx)
+	require.NoError(t, err)
+
+	h.Migrations.Config.Seeds = path.Join(h.Path, "seeds")
+
+	_, err = h.Migrations.Seed(h.Ctx, migrations.SeedInput{})
+	require.ErrorContains(t, err, "seeds directory not found")
+
+	require.NoError(t, os.MkdirAll(path.Join(h.Migrations.Config.Seeds, "emulator"), 0755))
+
+	require.NoError(t, os.WriteFile(
+		path.Join(h.Migrations.Config.Seeds, "test.yaml"),
+		[]byte("- id: one\n  name: One\n  update_time: PENDING_COMMIT_TIMESTAMP()\n"),
+		0644,
+	))
+
+	require.NoError(t, os.WriteFile(
+		path.Join(h.Migrations.Config.Seeds, "emulator", "test.csv"),
+		[]byte("id,name,update_time\ntwo,Two,2026-10-18T15:30:00Z\n"),
+		0644,
+	))
+
+	seeds, err := h.Migrations.Seed(h.Ctx, migrations.SeedInput{Env: jimmyv1.Environment_EMULATOR})
+	require.NoError(t, err)
+	require.Len(t, seeds, 2)
+	require.Equal(t, "test.yaml", seeds[0].Path)
+	require.Equal(t, "test", seeds[0].Table)
+	require.Equal(t, 1, seeds[0].Rows)
+	require.False(t, seeds[0].Skipped)
+	require.Equal(t, "emulator/test.csv", seeds[1].Path)
+	require.Equal(t, 1, seeds[1].Rows)
+
+	seeds, err = h.Migrations.Seed(h.Ctx, migrations.SeedInput{Env: jimmyv1.Environment_EMULATOR})
+	require.NoError(t, err)
+	require.Len(t, seeds, 2)
+	require.True(t, seeds[0].Skipped)
+	require.True(t, seeds[1].Skipped)
+
+	db, err := h.Migrations.Database(h.Ctx)
+	require.NoError(t, err)
+
+	var names []string
+
+	err = db.Single().Read(h.Ctx, "test", spanner.AllKeys(), []string{"name"}).Do(func(r *spanner.Row) error {
+		var name string
+		err := r.Columns(&name)
+		names = append(names, name)
+		return err
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"One", "Two"}, names)
+
+	schema, err := h.Migrations.Schema(h.Ctx)
+	require.NoError(t, err)
+	require.Len(t, schema.Statements, 1)
+}
+
+func TestMigrations_Seed_executor(t *testing.T) {
+	h := offlineHelper(t)
+	ctx := h.Ctx
+	ms := h.Migrations
+	ms.Config.Seeds = path.Join(h.Path, "seeds")
+
+	executor := migrationstest.NewExecutor()
+	executor.Columns["users"] = map[string]string{"id": "INT64", "name": "STRING(MAX)"}
+	ms.SetExecutor(executor)
+
+	content := []byte("- id: 1\n  name: Alice\n- id: 2\n  name: Bob\n")
+
+	require.NoError(t, os.MkdirAll(ms.Config.Seeds, 0755))
+	require.NoError(t, os.WriteFile(path.Join(ms.Config.Seeds, "users.yaml"), content, 0644))
+
+	seeds, err := ms.Seed(ctx, migrations.SeedInput{Env: jimmyv1.Environment_EMULATOR})
+	require.NoError(t, err)
+	require.Len(t, seeds, 1)
+	require.Equal(t, "users", seeds[0].Table)
+	require.Equal(t, 2, seeds[0].Rows)
+	require.False(t, seeds[0].Skipped)
+
+	calls := executor.Calls()
+	require.Len(t, calls, 3)
+	require.Equal(t, "UpdateDDL", calls[0].Method)
+	require.Contains(t, calls[0].Statements[0], "CREATE TABLE IF NOT EXISTS migrations_seeds")
+	require.Equal(t, "Apply", calls[1].Method)
+	require.Len(t, calls[1].Mutations, 2)
+	require.Equal(t, "Apply", calls[2].Method)
+	require.Len(t, calls[2].Mutations, 1)
+
+	sum := sha256.Sum256(content)
+	executor.Checksums["migrations_seeds"] = map[string]string{"users.yaml": hex.EncodeToString(sum[:])}
+
+	seeds, err = ms.Seed(ctx, migrations.SeedInput{Env: jimmyv1.Environment_EMULATOR})
+	require.NoError(t, err)
+	require.Len(t, seeds, 1)
+	require.True(t, seeds[0].Skipped)
+	require.Len(t, executor.Calls(), 3)
+}
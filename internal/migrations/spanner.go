@@ -2,6 +2,7 @@ package migrations
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 
@@ -61,17 +62,7 @@ func (ms *Migrations) ensureAll(ctx context.Context) error {
 		return err
 	}
 
-	err = ms.ensureInstance(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to ensure instance: %w", err)
-	}
-
-	err = ms.ensureDatabase(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to ensure database: %w", err)
-	}
-
-	return nil
+	return ms.executor().EnsureDatabase(ctx)
 }
 
 func (ms *Migrations) ensureEnv(_ context.Context) error {
@@ -83,8 +74,14 @@ func (ms *Migrations) ensureEnv(_ context.Context) error {
 	return nil
 }
 
+// createEnabled returns whether missing instances and databases are
+// created, which is always the case for the emulator.
+func (ms *Migrations) createEnabled() bool {
+	return ms.emulator || ms.Config.GetCreateDatabase().GetEnabled()
+}
+
 func (ms *Migrations) ensureInstance(ctx context.Context) error {
-	if !ms.emulator || ms.instanceEnsured {
+	if !ms.createEnabled() || ms.instanceEnsured {
 		return nil
 	}
 	ms.instanceEnsured = true
@@ -102,9 +99,31 @@ func (ms *Migrations) ensureInstance(ctx context.Context) error {
 	}
 
 	if inst == nil {
+		create := ms.Config.GetCreateDatabase()
+
+		instanceConfig := create.GetInstanceConfig()
+		if instanceConfig == "" {
+			if !ms.emulator {
+				return errors.New("instance config is required to create an instance")
+			}
+
+			instanceConfig = constants.EmulatorInstanceConfig
+		}
+
+		processingUnits := create.GetProcessingUnits()
+		if processingUnits == 0 {
+			processingUnits = constants.ProcessingUnits
+		}
+
 		op, err := instanceAdmin.CreateInstance(ctx, &instancepb.CreateInstanceRequest{
 			Parent:     ms.InstancesName(),
 			InstanceId: ms.Config.InstanceId,
+			Instance: &instancepb.Instance{
+				Name:            ms.InstanceName(),
+				Config:          fmt.Sprintf("%s/instanceConfigs/%s", ms.ProjectName(), instanceConfig),
+				DisplayName:     ms.Config.InstanceId,
+				ProcessingUnits: processingUnits,
+			},
 		})
 		if err != nil {
 			return err
@@ -120,7 +139,7 @@ func (ms *Migrations) ensureInstance(ctx context.Context) error {
 }
 
 func (ms *Migrations) ensureDatabase(ctx context.Context) error {
-	if !ms.emulator || ms.databaseEnsured {
+	if !ms.createEnabled() || ms.databaseEnsured {
 		return nil
 	}
 	ms.databaseEnsured = true
@@ -138,9 +157,16 @@ func (ms *Migrations) ensureDatabase(ctx context.Context) error {
 	}
 
 	if db == nil {
+		err = checkDialect(ms.Config.GetCreateDatabase())
+		if err != nil {
+			return err
+		}
+
 		op, err := dbAdmin.CreateDatabase(ctx, &databasepb.CreateDatabaseRequest{
 			Parent:          ms.InstanceName(),
 			CreateStatement: fmt.Sprintf("CREATE DATABASE %s", ms.Config.DatabaseId),
+			ExtraStatements: ms.Config.GetCreateDatabase().GetExtraStatements(),
+			DatabaseDialect: databasepb.DatabaseDialect_GOOGLE_STANDARD_SQL,
 		})
 		if err != nil {
 			return err
@@ -156,23 +182,11 @@ func (ms *Migrations) ensureDatabase(ctx context.Context) error {
 }
 
 func (ms *Migrations) ensureTable(ctx context.Context) error {
-	db, err := ms.Database(ctx)
-	if err != nil {
-		return err
-	}
-
-	var exists bool
+	return ms.executor().EnsureTable(ctx, ms.Config.Table)
+}
 
-	err = db.Single().Query(ctx, spanner.Statement{
-		SQL: constants.SelectMigrationsTable,
-		Params: map[string]any{
-			"tableSchema": "",
-			"tableName":   ms.Config.Table,
-		},
-	}).Do(func(r *spanner.Row) error {
-		exists = true
-		return nil
-	})
+func (ms *Migrations) ensureTableDDL(ctx context.Context, table, sql string) error {
+	exists, err := ms.executor().TableExists(ctx, table)
 	if err != nil {
 		return err
 	}
@@ -181,25 +195,15 @@ func (ms *Migrations) ensureTable(ctx context.Context) error {
 		return nil
 	}
 
-	dbAdmin, err := ms.DatabaseAdmin(ctx)
-	if err != nil {
-		return err
-	}
-
-	op, err := dbAdmin.UpdateDatabaseDdl(ctx, &databasepb.UpdateDatabaseDdlRequest{
-		Database: ms.DatabaseName(),
-		Statements: []string{
-			fmt.Sprintf(constants.CreateMigrationTable, ms.Config.Table),
-		},
-	})
-	if err != nil {
-		return err
-	}
+	return ms.runDDL(ctx, fmt.Sprintf(sql, table))
+}
 
-	err = op.Wait(ctx)
+// runDDL runs DDL statements and waits for them to complete.
+func (ms *Migrations) runDDL(ctx context.Context, statements ...string) error {
+	operation, err := ms.executor().UpdateDDL(ctx, statements, nil)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return ms.executor().WaitDDL(ctx, operation)
 }
@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
+)
+
+func TestCreateEnabled(t *testing.T) {
+	ctx := context.Background()
+
+	ms := New("")
+	ms.emulator = false
+	ms.Config.ProjectId = "test"
+	ms.Config.InstanceId = "test"
+	ms.Config.DatabaseId = "test"
+
+	require.False(t, ms.createEnabled())
+
+	// nothing is looked up or created unless enabled
+	require.NoError(t, ms.ensureInstance(ctx))
+	require.NoError(t, ms.ensureDatabase(ctx))
+	require.Nil(t, ms.instanceAdmin)
+	require.Nil(t, ms.databaseAdmin)
+
+	ms.Config.CreateDatabase = &jimmyv1.CreateDatabase{}
+	require.False(t, ms.createEnabled())
+
+	ms.Config.CreateDatabase.Enabled = true
+	require.True(t, ms.createEnabled())
+
+	ms.Config.CreateDatabase = nil
+	ms.emulator = true
+	require.True(t, ms.createEnabled())
+}
@@ -2,6 +2,7 @@ package migrations
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
@@ -46,3 +47,48 @@ func (ms *Migrations) newStatement(
 
 	return stmt, nil
 }
+
+// environment returns the detected built-in environment.
+func (ms *Migrations) environment() jimmyv1.Environment {
+	if ms.emulator {
+		return jimmyv1.Environment_EMULATOR
+	}
+	return jimmyv1.Environment_GOOGLE_CLOUD
+}
+
+// isEnabled returns whether the statement runs in the built-in environment
+// and the active user-defined environment.
+func (ms *Migrations) isEnabled(env jimmyv1.Environment, s *jimmyv1.Statement) (bool, error) {
+	switch s.Env {
+	case jimmyv1.Environment_ALL:
+		// ok
+	case jimmyv1.Environment_GOOGLE_CLOUD, jimmyv1.Environment_EMULATOR:
+		if s.Env != env {
+			return false, nil
+		}
+	default:
+		return false, fmt.Errorf("unhandled environment %s", s.Env.String())
+	}
+
+	if len(s.Envs) > 0 && !slices.Contains(s.Envs, ms.Env) {
+		return false, nil
+	}
+
+	if ms.Env != "" && slices.Contains(s.ExcludeEnvs, ms.Env) {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// checkEnvs returns an error if an environment isn't declared in the
+// configuration.
+func (ms *Migrations) checkEnvs(envs ...string) error {
+	for _, env := range envs {
+		if !slices.Contains(ms.Config.GetEnvironments(), env) {
+			return fmt.Errorf("%q environment isn't declared", env)
+		}
+	}
+
+	return nil
+}
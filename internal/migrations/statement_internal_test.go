@@ -0,0 +1,83 @@
+package migrations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
+)
+
+func TestIsEnabled(t *testing.T) {
+	ms := &Migrations{
+		Config: &jimmyv1.Config{
+			Environments: []string{"dev", "staging", "prod"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		env      string
+		emulator bool
+		stmt     *jimmyv1.Statement
+		enabled  bool
+	}{
+		{name: "all", stmt: &jimmyv1.Statement{}, enabled: true},
+		{name: "emulator", stmt: &jimmyv1.Statement{Env: jimmyv1.Environment_EMULATOR}},
+		{name: "emulator enabled", emulator: true, stmt: &jimmyv1.Statement{Env: jimmyv1.Environment_EMULATOR}, enabled: true},
+		{name: "envs no env", stmt: &jimmyv1.Statement{Envs: []string{"prod"}}},
+		{name: "envs match", env: "prod", stmt: &jimmyv1.Statement{Envs: []string{"staging", "prod"}}, enabled: true},
+		{name: "envs no match", env: "dev", stmt: &jimmyv1.Statement{Envs: []string{"staging", "prod"}}},
+		{name: "exclude no env", stmt: &jimmyv1.Statement{ExcludeEnvs: []string{"prod"}}, enabled: true},
+		{name: "exclude match", env: "prod", stmt: &jimmyv1.Statement{ExcludeEnvs: []string{"prod"}}},
+		{name: "exclude no match", env: "dev", stmt: &jimmyv1.Statement{ExcludeEnvs: []string{"prod"}}, enabled: true},
+		{
+			name:     "emulator and envs",
+			env:      "dev",
+			emulator: false,
+			stmt:     &jimmyv1.Statement{Env: jimmyv1.Environment_EMULATOR, Envs: []string{"dev"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ms.Env = test.env
+			ms.emulator = test.emulator
+
+			enabled, err := ms.isEnabled(ms.environment(), test.stmt)
+			require.NoError(t, err)
+			require.Equal(t, test.enabled, enabled)
+		})
+	}
+}
+
+func TestSetTarget(t *testing.T) {
+	ms := &Migrations{
+		Config: &jimmyv1.Config{
+			ProjectId:    "project",
+			InstanceId:   "instance",
+			DatabaseId:   "database",
+			Environments: []string{"staging"},
+			Targets: map[string]*jimmyv1.Target{
+				"staging": {
+					DatabaseId: "staging",
+					Env:        "staging",
+				},
+			},
+		},
+	}
+
+	err := ms.SetTarget("prod")
+	require.EqualError(t, err, `"prod" target not found`)
+
+	err = ms.SetTarget("staging")
+	require.NoError(t, err)
+	require.Equal(t, "project", ms.Config.ProjectId)
+	require.Equal(t, "instance", ms.Config.InstanceId)
+	require.Equal(t, "staging", ms.Config.DatabaseId)
+	require.Equal(t, "staging", ms.Env)
+
+	err = ms.SetEnv("prod")
+	require.EqualError(t, err, `"prod" environment isn't declared`)
+	require.Equal(t, "staging", ms.Env)
+}
@@ -0,0 +1,79 @@
+package migrations
+
+import (
+	"context"
+	"maps"
+	"slices"
+)
+
+type Status struct {
+	// CurrentID is the highest ID in the migration table.
+	CurrentID int
+
+	// Incomplete contains the IDs of migrations that started but never
+	// completed.
+	Incomplete []int
+
+	// Pending contains the migrations an upgrade would run, in order.
+	Pending []*Migration
+
+	// OutOfOrder contains the pending migrations with an ID lower than the
+	// current ID.
+	OutOfOrder []*Migration
+
+	// DatabaseOptions contains the configured database options that differ
+	// from the database.
+	DatabaseOptions []*DatabaseOption
+}
+
+func (ms *Migrations) Status(ctx context.Context) (*Status, error) {
+	err := ms.ensureAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := ms.status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status.DatabaseOptions, err = ms.DatabaseOptionsDrift(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+func (ms *Migrations) status(ctx context.Context) (*Status, error) {
+	applied, err := ms.AppliedIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &Status{}
+
+	for _, id := range slices.Sorted(maps.Keys(applied)) {
+		status.CurrentID = id
+
+		if !applied[id] {
+			status.Incomplete = append(status.Incomplete, id)
+		}
+	}
+
+	status.Pending, err = ms.pending(func(id int) bool {
+		_, found := applied[id]
+		return found
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range status.Pending {
+		if m.ID() < status.CurrentID {
+			status.OutOfOrder = append(status.OutOfOrder, m)
+		}
+	}
+
+	return status, nil
+}
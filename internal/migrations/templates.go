@@ -1,11 +1,20 @@
 package migrations
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"iter"
 	"maps"
+	"os"
+	"regexp"
 	"slices"
+	"strings"
 
+	"buf.build/go/protoyaml"
+	"github.com/bufbuild/protovalidate-go"
 	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
 
 	"github.com/silas/jimmy/internal/constants"
 	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
@@ -71,3 +80,255 @@ func templateSeq(templates map[string]*jimmyv1.Template) iter.Seq2[string, *jimm
 		}
 	}
 }
+
+const (
+	TemplateSourceBuiltin = "builtin"
+	TemplateSourceConfig  = "config"
+)
+
+var templateIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Template returns a template and whether it's builtin or from the
+// configuration.
+func (ms *Migrations) Template(templateID string) (*jimmyv1.Template, string, error) {
+	for id, template := range ms.Templates() {
+		if id != templateID {
+			continue
+		}
+
+		source := TemplateSourceBuiltin
+		if _, found := ms.Config.GetTemplates()[templateID]; found {
+			source = TemplateSourceConfig
+		}
+
+		return template, source, nil
+	}
+
+	return nil, "", fmt.Errorf("%q template not found", templateID)
+}
+
+type AddTemplateInput struct {
+	ID      string
+	SQL     string
+	Env     jimmyv1.Environment
+	Type    jimmyv1.Type
+	Default bool
+}
+
+// AddTemplate adds a template to the configuration file.
+func (ms *Migrations) AddTemplate(input AddTemplateInput) error {
+	if !templateIDPattern.MatchString(input.ID) {
+		return fmt.Errorf("%q is not a valid template ID", input.ID)
+	}
+
+	template := &jimmyv1.Template{
+		Sql:  strings.TrimSpace(input.SQL) + "\n",
+		Env:  input.Env,
+		Type: input.Type,
+	}
+
+	if input.Default {
+		template.Default = Ref(true)
+	}
+
+	validator, err := protovalidate.New()
+	if err != nil {
+		return err
+	}
+
+	err = validator.Validate(template)
+	if err != nil {
+		return err
+	}
+
+	return ms.updateTemplates(func(templates map[string]*jimmyv1.Template) error {
+		if _, found := templates[input.ID]; found {
+			return fmt.Errorf("%q template already exists", input.ID)
+		}
+
+		templates[input.ID] = template
+
+		return nil
+	})
+}
+
+// RemoveTemplate removes a template from the configuration file.
+func (ms *Migrations) RemoveTemplate(templateID string) error {
+	return ms.updateTemplates(func(templates map[string]*jimmyv1.Template) error {
+		if _, found := templates[templateID]; !found {
+			if _, found := builtinTemplates[templateID]; found {
+				return fmt.Errorf("%q is a builtin template and can't be removed", templateID)
+			}
+
+			return fmt.Errorf("%q template not found", templateID)
+		}
+
+		delete(templates, templateID)
+
+		return nil
+	})
+}
+
+// SetDefaultTemplate marks a template as the default in the configuration
+// file, copying builtin templates into it when needed.
+func (ms *Migrations) SetDefaultTemplate(templateID string) error {
+	return ms.updateTemplates(func(templates map[string]*jimmyv1.Template) error {
+		template, found := templates[templateID]
+		if !found {
+			builtin, found := builtinTemplates[templateID]
+			if !found {
+				return fmt.Errorf("%q template not found", templateID)
+			}
+
+			template = proto.Clone(builtin).(*jimmyv1.Template)
+		}
+
+		for _, t := range templates {
+			t.Default = nil
+		}
+
+		// the builtin default is used when no template is marked
+		if !found && templateID == builtinDefaultTemplate {
+			return nil
+		}
+
+		template.Default = Ref(true)
+		templates[templateID] = template
+
+		return nil
+	})
+}
+
+// updateTemplates changes the templates in the configuration file. Only
+// the templates key is rewritten, so comments, ordering and settings
+// overridden by flags and targets are kept.
+func (ms *Migrations) updateTemplates(update func(templates map[string]*jimmyv1.Template) error) error {
+	b, err := os.ReadFile(ms.Path)
+	if err != nil {
+		return err
+	}
+
+	config := &jimmyv1.Config{}
+
+	err = Unmarshal(ms.Path, config)
+	if err != nil {
+		return err
+	}
+
+	if config.Templates == nil {
+		config.Templates = map[string]*jimmyv1.Template{}
+	}
+
+	err = update(config.Templates)
+	if err != nil {
+		return err
+	}
+
+	err = errors.Join(validateTemplates(config.Templates)...)
+	if err != nil {
+		return err
+	}
+
+	templatesData, err := protoyaml.MarshalOptions{
+		Indent: 2,
+	}.Marshal(&jimmyv1.Config{Templates: config.Templates})
+	if err != nil {
+		return err
+	}
+
+	var doc, templatesDoc yaml.Node
+
+	err = yaml.Unmarshal(b, &doc)
+	if err != nil {
+		return fmt.Errorf("failed to parse %q: %w", ms.Path, err)
+	}
+
+	err = yaml.Unmarshal(templatesData, &templatesDoc)
+	if err != nil {
+		return err
+	}
+
+	err = setYAMLKey(&doc, "templates", yamlKey(&templatesDoc, "templates"))
+	if err != nil {
+		return fmt.Errorf("failed to update %q: %w", ms.Path, err)
+	}
+
+	var buf bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+
+	err = encoder.Encode(&doc)
+	if err != nil {
+		return err
+	}
+
+	err = encoder.Close()
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(ms.Path, buf.Bytes(), 0644)
+	if err != nil {
+		return err
+	}
+
+	ms.Config.Templates = config.Templates
+
+	return nil
+}
+
+// yamlKey returns the value of a key in a YAML document mapping, or nil if
+// it isn't set.
+func yamlKey(doc *yaml.Node, key string) *yaml.Node {
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil
+	}
+
+	mapping := doc.Content[0]
+
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+// setYAMLKey sets the value of a key in a YAML document mapping, removing
+// the key when the value is nil.
+func setYAMLKey(doc *yaml.Node, key string, value *yaml.Node) error {
+	if doc.Kind == 0 {
+		doc.Kind = yaml.DocumentNode
+	}
+
+	if len(doc.Content) == 0 {
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+
+	mapping := doc.Content[0]
+	if mapping.Kind != yaml.MappingNode {
+		return errors.New("expected a mapping")
+	}
+
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value != key {
+			continue
+		}
+
+		if value == nil {
+			mapping.Content = slices.Delete(mapping.Content, i, i+2)
+		} else {
+			mapping.Content[i+1] = value
+		}
+
+		return nil
+	}
+
+	if value != nil {
+		mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+	}
+
+	return nil
+}
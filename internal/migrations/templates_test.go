@@ -2,6 +2,7 @@ package migrations_test
 
 import (
 	"iter"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -58,3 +59,85 @@ func TestMigrations_Templates(t *testing.T) {
 		})
 	}
 }
+
+func TestManageTemplates(t *testing.T) {
+	h := offlineHelper(t)
+	ctx := h.Ctx
+	ms := h.Migrations
+
+	config, err := os.ReadFile(ms.Path)
+	require.NoError(t, err)
+
+	// comments and settings outside of templates are kept
+	config = append([]byte("# project settings\n"), config...)
+	require.NoError(t, os.WriteFile(ms.Path, config, 0644))
+
+	defaultTemplateID := func() string {
+		loaded := migrations.New(ms.Path)
+		require.NoError(t, loaded.Load(ctx))
+		require.NoError(t, loaded.Validate())
+
+		for templateID, template := range loaded.Templates() {
+			if template.GetDefault() {
+				return templateID
+			}
+		}
+
+		return ""
+	}
+
+	require.NoError(t, ms.AddTemplate(migrations.AddTemplateInput{
+		ID:      "insert-user",
+		SQL:     "INSERT INTO users (id) VALUES (1)",
+		Type:    jimmyv1.Type_DML,
+		Default: true,
+	}))
+	require.Equal(t, "insert-user", defaultTemplateID())
+
+	template, source, err := ms.Template("insert-user")
+	require.NoError(t, err)
+	require.Equal(t, migrations.TemplateSourceConfig, source)
+	require.Equal(t, "INSERT INTO users (id) VALUES (1)\n", template.GetSql())
+	require.Equal(t, jimmyv1.Type_DML, template.GetType())
+	require.True(t, template.GetDefault())
+
+	_, source, err = ms.Template("drop-table")
+	require.NoError(t, err)
+	require.Equal(t, migrations.TemplateSourceBuiltin, source)
+
+	err = ms.AddTemplate(migrations.AddTemplateInput{ID: "insert-user", SQL: "SELECT 1"})
+	require.EqualError(t, err, `"insert-user" template already exists`)
+
+	err = ms.AddTemplate(migrations.AddTemplateInput{ID: "Bad ID", SQL: "SELECT 1"})
+	require.EqualError(t, err, `"Bad ID" is not a valid template ID`)
+
+	err = ms.AddTemplate(migrations.AddTemplateInput{ID: "other", SQL: "SELECT 1", Default: true})
+	require.EqualError(t, err, `"insert-user" and "other" can't both be marked as the default template`)
+
+	require.NoError(t, ms.SetDefaultTemplate("drop-table"))
+	require.Equal(t, "drop-table", defaultTemplateID())
+
+	_, source, err = ms.Template("drop-table")
+	require.NoError(t, err)
+	require.Equal(t, migrations.TemplateSourceConfig, source)
+
+	require.NoError(t, ms.SetDefaultTemplate("create-table"))
+	require.Equal(t, "create-table", defaultTemplateID())
+
+	err = ms.SetDefaultTemplate("missing")
+	require.EqualError(t, err, `"missing" template not found`)
+
+	require.NoError(t, ms.RemoveTemplate("insert-user"))
+	require.NoError(t, ms.RemoveTemplate("drop-table"))
+
+	err = ms.RemoveTemplate("create-table")
+	require.EqualError(t, err, `"create-table" is a builtin template and can't be removed`)
+
+	_, _, err = ms.Template("insert-user")
+	require.EqualError(t, err, `"insert-user" template not found`)
+	require.Equal(t, "create-table", defaultTemplateID())
+
+	updated, err := os.ReadFile(ms.Path)
+	require.NoError(t, err)
+	require.Equal(t, string(config), string(updated))
+}
@@ -2,14 +2,17 @@ package migrations
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
 
 	"cloud.google.com/go/spanner"
-	"cloud.google.com/go/spanner/admin/database/apiv1/databasepb"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/descriptorpb"
 
-	"github.com/silas/jimmy/internal/constants"
 	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
 )
 
@@ -17,10 +20,33 @@ type OnMigration func(m *Migration)
 
 type OnMigrationBatch func(m *Migration, batch *Batch)
 
+type OnMigrationWhen func(m *Migration, pos int, result bool)
+
+type OnMigrationOperation func(m *Migration, operation string)
+
+type OnDatabaseOptions func(options []*DatabaseOption)
+
+type OnMigrationProgress func(m *Migration, s *jimmyv1.Statement, iteration int, rowCount int64)
+
+var ErrOutOfOrder = errors.New("unapplied migrations")
+
+var ErrAssertion = errors.New("assertion failed")
+
+var ErrPendingOperation = errors.New("pending operation")
+
 type upgradeOptions struct {
-	onStart    OnMigration
-	onBatch    OnMigrationBatch
-	onComplete OnMigration
+	onStart         OnMigration
+	onBatch         OnMigrationBatch
+	onComplete      OnMigration
+	onWhen          OnMigrationWhen
+	onProgress      OnMigrationProgress
+	onOperation     OnMigrationOperation
+	onReattach      OnMigrationOperation
+	onOptions       OnDatabaseOptions
+	onBackup        OnMigrationBackup
+	allowOutOfOrder bool
+	noWait          bool
+	backup          bool
 }
 
 type UpgradeOption func(o *upgradeOptions)
@@ -43,6 +69,79 @@ func UpgradeOnComplete(onComplete OnMigration) UpgradeOption {
 	}
 }
 
+// UpgradeOnWhen sets a function called with the result of each statement's
+// when query.
+func UpgradeOnWhen(onWhen OnMigrationWhen) UpgradeOption {
+	return func(o *upgradeOptions) {
+		o.onWhen = onWhen
+	}
+}
+
+// UpgradeOnProgress sets a function called after each iteration of a
+// batched DML statement.
+func UpgradeOnProgress(onProgress OnMigrationProgress) UpgradeOption {
+	return func(o *upgradeOptions) {
+		o.onProgress = onProgress
+	}
+}
+
+// UpgradeOnOperation sets a function called when a DDL operation is
+// submitted without waiting for it to complete.
+func UpgradeOnOperation(onOperation OnMigrationOperation) UpgradeOption {
+	return func(o *upgradeOptions) {
+		o.onOperation = onOperation
+	}
+}
+
+// UpgradeOnReattach sets a function called when an upgrade waits for the
+// DDL operation of an interrupted migration.
+func UpgradeOnReattach(onReattach OnMigrationOperation) UpgradeOption {
+	return func(o *upgradeOptions) {
+		o.onReattach = onReattach
+	}
+}
+
+// UpgradeOnDatabaseOptions sets a function called with the database
+// options changed to match the configuration.
+func UpgradeOnDatabaseOptions(onOptions OnDatabaseOptions) UpgradeOption {
+	return func(o *upgradeOptions) {
+		o.onOptions = onOptions
+	}
+}
+
+// UpgradeOnBackup sets a function called with the restore point recorded
+// before a migration.
+func UpgradeOnBackup(onBackup OnMigrationBackup) UpgradeOption {
+	return func(o *upgradeOptions) {
+		o.onBackup = onBackup
+	}
+}
+
+// UpgradeBackup sets whether a restore point is recorded before the first
+// pending migration, in addition to migrations with backup_before set.
+func UpgradeBackup(backup bool) UpgradeOption {
+	return func(o *upgradeOptions) {
+		o.backup = backup
+	}
+}
+
+// UpgradeNoWait sets whether the upgrade stops after submitting the final
+// DDL batch of a migration instead of waiting for it to complete, leaving
+// the operation to be tracked with WaitOperations.
+func UpgradeNoWait(noWait bool) UpgradeOption {
+	return func(o *upgradeOptions) {
+		o.noWait = noWait
+	}
+}
+
+// UpgradeAllowOutOfOrder sets whether migrations older than the current
+// migration that haven't been applied are run.
+func UpgradeAllowOutOfOrder(allowOutOfOrder bool) UpgradeOption {
+	return func(o *upgradeOptions) {
+		o.allowOutOfOrder = allowOutOfOrder
+	}
+}
+
 func (ms *Migrations) Upgrade(ctx context.Context, opts ...UpgradeOption) error {
 	o := &upgradeOptions{}
 
@@ -60,132 +159,302 @@ func (ms *Migrations) Upgrade(ctx context.Context, opts ...UpgradeOption) error
 		return fmt.Errorf("failed to ensure migration table: %w", err)
 	}
 
-	currentID, err := ms.getCurrentID(ctx)
+	options, err := ms.applyDatabaseOptions(ctx)
 	if err != nil {
 		return err
 	}
 
-	id := currentID
+	if len(options) > 0 && o.onOptions != nil {
+		o.onOptions(options)
+	}
 
-	for id < ms.latestID {
-		id++
-		startID := id
+	status, err := ms.status(ctx)
+	if err != nil {
+		return err
+	}
 
-		if skipID := ms.squash[id]; skipID > id {
-			id = skipID
+	for _, id := range status.Incomplete {
+		m, err := ms.Get(id)
+		if err != nil {
+			return fmt.Errorf("migration %d is incomplete: %w", id, err)
 		}
 
-		m, err := ms.Get(id)
+		operation, err := ms.resumeMigration(ctx, m, o)
 		if err != nil {
 			return err
 		}
 
-		squashID, found := m.SquashID()
-		if found && squashID != startID {
-			continue
+		if operation != "" {
+			return nil
 		}
+	}
 
-		if o.onStart != nil {
-			o.onStart(m)
+	if len(status.OutOfOrder) > 0 && !o.allowOutOfOrder {
+		var ids []string
+
+		for _, m := range status.OutOfOrder {
+			ids = append(ids, strconv.Itoa(m.ID()))
 		}
 
-		err = ms.startMigration(ctx, id)
+		return fmt.Errorf(
+			"%w: %s older than the current migration %d",
+			ErrOutOfOrder,
+			strings.Join(ids, ", "),
+			status.CurrentID,
+		)
+	}
+
+	for i, m := range status.Pending {
+		start, err := ms.checkAssertions(ctx, m, o)
 		if err != nil {
 			return err
 		}
 
-		batch := &Batch{}
+		var backup *Backup
 
-		for pos, s := range m.data.Upgrade {
-			switch s.Env {
-			case jimmyv1.Environment_ALL:
-				// ok
-			case jimmyv1.Environment_GOOGLE_CLOUD:
-				if ms.emulator {
-					continue
-				}
-			case jimmyv1.Environment_EMULATOR:
-				if !ms.emulator {
-					continue
-				}
-			default:
-				return fmt.Errorf("unhandled environment %d upgrade[%d]: %s",
-					id, pos, s.Env.String())
-			}
-
-			if s.Type == jimmyv1.Type_AUTOMATIC {
-				s.Type = detectType(s.Sql)
+		if (o.backup && i == 0) || m.data.GetBackupBefore() {
+			backup, err = ms.backup(ctx, m)
+			if err != nil {
+				return fmt.Errorf("migration %d: %w", m.ID(), err)
 			}
 
-			if batch.flush(s) {
-				err = ms.runBatch(ctx, m, batch, o.onBatch)
-				if err != nil {
-					return err
-				}
-
-				batch.reset()
+			if o.onBackup != nil {
+				o.onBackup(m, backup)
 			}
+		}
 
-			batch.add(s)
+		if o.onStart != nil {
+			o.onStart(m)
 		}
 
-		err = ms.runBatch(ctx, m, batch, o.onBatch)
+		err = ms.startMigration(ctx, m.ID())
 		if err != nil {
 			return err
 		}
 
-		err = ms.completeMigration(ctx, id)
+		if backup != nil {
+			err = ms.setBackup(ctx, m.ID(), backup)
+			if err != nil {
+				return err
+			}
+		}
+
+		operation, err := ms.runMigration(ctx, m, start, o)
 		if err != nil {
 			return err
 		}
 
-		if o.onComplete != nil {
-			o.onComplete(m)
+		if operation != "" {
+			return nil
 		}
 	}
 
 	return nil
 }
 
-func (ms *Migrations) getCurrentID(ctx context.Context) (int, error) {
-	db, err := ms.Database(ctx)
+// checkAssertions runs the assertions at the start of a migration before
+// it's started, so a failed assertion leaves nothing to clean up, and
+// returns the position of the first other statement.
+func (ms *Migrations) checkAssertions(ctx context.Context, m *Migration, o *upgradeOptions) (int, error) {
+	batch := &Batch{}
+
+	for pos, s := range m.data.Upgrade {
+		enabled, err := ms.isEnabled(ms.environment(), s)
+		if err != nil {
+			return 0, fmt.Errorf("migration %d upgrade[%d]: %w", m.ID(), pos, err)
+		}
+
+		if !enabled {
+			continue
+		}
+
+		if s.Type != jimmyv1.Type_ASSERT || s.When != nil {
+			return pos, ms.runBatch(ctx, m, batch, o)
+		}
+
+		batch.add(s)
+	}
+
+	return len(m.data.Upgrade), ms.runBatch(ctx, m, batch, o)
+}
+
+// runMigration runs the upgrade statements of a migration from the given
+// position and completes the migration, unless the final DDL batch was
+// submitted without waiting, in which case the operation name is returned.
+func (ms *Migrations) runMigration(ctx context.Context, m *Migration, start int, o *upgradeOptions) (string, error) {
+	id := m.ID()
+
+	batch := &Batch{}
+
+	for pos, s := range m.data.Upgrade {
+		if pos < start {
+			continue
+		}
+
+		enabled, err := ms.isEnabled(ms.environment(), s)
+		if err != nil {
+			return "", fmt.Errorf("migration %d upgrade[%d]: %w", id, pos, err)
+		}
+
+		if !enabled {
+			continue
+		}
+
+		if s.Type == jimmyv1.Type_AUTOMATIC {
+			s.Type = detectType(s.Sql)
+		}
+
+		if s.When != nil {
+			// run earlier statements so the query sees their changes
+			err = ms.runBatch(ctx, m, batch, o)
+			if err != nil {
+				return "", err
+			}
+
+			batch.reset()
+
+			result, err := ms.evaluateWhen(ctx, s)
+			if err != nil {
+				return "", fmt.Errorf("migration %d upgrade[%d] when: %w", id, pos, err)
+			}
+
+			if o.onWhen != nil {
+				o.onWhen(m, pos, result)
+			}
+
+			if !result {
+				continue
+			}
+		}
+
+		if batch.flush(s) {
+			err = ms.runBatch(ctx, m, batch, o)
+			if err != nil {
+				return "", err
+			}
+
+			batch.reset()
+		}
+
+		batch.add(s)
+	}
+
+	if o.noWait && len(batch.Statements) > 0 && batch.Statements[0].Type == jimmyv1.Type_DDL {
+		if o.onBatch != nil {
+			o.onBatch(m, batch)
+		}
+
+		operation, err := ms.submitDDL(ctx, m, batch)
+		if err != nil {
+			return "", err
+		}
+
+		if o.onOperation != nil {
+			o.onOperation(m, operation)
+		}
+
+		return operation, nil
+	}
+
+	err := ms.runBatch(ctx, m, batch, o)
 	if err != nil {
-		return 0, err
+		return "", err
+	}
+
+	err = ms.completeMigration(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	if o.onComplete != nil {
+		o.onComplete(m)
 	}
 
-	var currentID int64
-	var complete bool
+	return "", nil
+}
 
-	err = db.Single().Query(ctx, spanner.Statement{
-		SQL: fmt.Sprintf(constants.SelectMigration, ms.Config.Table),
-	}).Do(func(r *spanner.Row) error {
-		return r.Columns(&currentID, &complete)
+// sequence returns the migrations to run after the current ID, jumping
+// directly to a squash migration when its squash ID is reached.
+func (ms *Migrations) sequence(currentID int) ([]*Migration, error) {
+	return ms.pending(func(id int) bool {
+		return id <= currentID
 	})
-	if err != nil {
-		return 0, err
+}
+
+// pending returns the migrations that haven't been applied in ID order,
+// jumping directly to a squash migration when its squash ID is reached.
+func (ms *Migrations) pending(applied func(id int) bool) ([]*Migration, error) {
+	ids := slices.Sorted(maps.Keys(ms.migrations))
+
+	done := map[int]bool{}
+
+	for _, id := range ids {
+		if applied(id) {
+			done[id] = true
+		}
 	}
 
-	if currentID > 0 && !complete {
-		return 0, fmt.Errorf("migration %d is incomplete", currentID)
+	// migrations skipped by an applied squash migration
+	for squashID, id := range ms.squash {
+		if done[id] {
+			for _, skippedID := range ids {
+				if skippedID >= squashID && skippedID < id {
+					done[skippedID] = true
+				}
+			}
+		}
 	}
 
-	return int(currentID), nil
+	var sequence []*Migration
+
+	for i := 0; i < len(ids); i++ {
+		id := ids[i]
+		if done[id] {
+			continue
+		}
+
+		startID := id
+
+		if skipID := ms.squash[id]; skipID > id {
+			i = slices.Index(ids, skipID)
+			id = skipID
+		}
+
+		m, err := ms.Get(id)
+		if err != nil {
+			return nil, err
+		}
+
+		squashID, found := m.SquashID()
+		if found && squashID != startID {
+			continue
+		}
+
+		sequence = append(sequence, m)
+	}
+
+	return sequence, nil
 }
 
-func (ms *Migrations) startMigration(ctx context.Context, id int) error {
-	db, err := ms.Database(ctx)
+// AppliedIDs returns the IDs of the migrations in the migration table,
+// mapped to whether the migration completed.
+func (ms *Migrations) AppliedIDs(ctx context.Context) (map[int]bool, error) {
+	tracked, err := ms.executor().TrackedMigrations(ctx, ms.Config.Table)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	_, err = db.Apply(ctx, []*spanner.Mutation{
-		spanner.Insert(
-			ms.Config.Table,
-			[]string{"id", "start_time"},
-			[]any{int64(id), spanner.CommitTimestamp},
-		),
-	})
-	return err
+	applied := map[int]bool{}
+
+	for _, t := range tracked {
+		applied[t.ID] = t.Complete
+	}
+
+	return applied, nil
+}
+
+func (ms *Migrations) startMigration(ctx context.Context, id int) error {
+	return ms.executor().StartMigration(ctx, ms.Config.Table, id)
 }
 
 type Batch struct {
@@ -228,62 +497,29 @@ func (ms *Migrations) runBatch(
 	ctx context.Context,
 	m *Migration,
 	batch *Batch,
-	onRun OnMigrationBatch,
+	o *upgradeOptions,
 ) error {
 	if batch == nil || len(batch.Statements) == 0 {
 		return nil
 	}
 
-	if onRun != nil {
-		onRun(m, batch)
+	if o.onBatch != nil {
+		o.onBatch(m, batch)
 	}
 
 	switch batch.Statements[0].Type {
 	case jimmyv1.Type_DDL:
-		var statements []string
-
-		for _, s := range batch.Statements {
-			statements = append(statements, s.Sql)
-		}
-
-		req := &databasepb.UpdateDatabaseDdlRequest{
-			Database:   ms.DatabaseName(),
-			Statements: statements,
-		}
-
-		// attach proto descriptors
-		if batch.FileDescriptorSet != "" {
-			id := batch.FileDescriptorSet
-
-			var fileDescriptorSet *descriptorpb.FileDescriptorSet
-
-			if len(m.data.FileDescriptorSets) > 0 {
-				fileDescriptorSet = m.data.FileDescriptorSets[id]
-			}
-
-			if fileDescriptorSet == nil {
-				return fmt.Errorf("file descriptor set %q not found", id)
-			}
-
-			b, err := proto.Marshal(fileDescriptorSet)
-			if err != nil {
-				return fmt.Errorf("failed to marshal %q file descriptor set", id)
-			}
-
-			req.ProtoDescriptors = b
-		}
-
-		dbAdmin, err := ms.DatabaseAdmin(ctx)
+		operation, err := ms.submitDDL(ctx, m, batch)
 		if err != nil {
 			return err
 		}
 
-		op, err := dbAdmin.UpdateDatabaseDdl(ctx, req)
+		err = ms.executor().WaitDDL(ctx, operation)
 		if err != nil {
 			return err
 		}
 
-		err = op.Wait(ctx)
+		err = ms.setOperation(ctx, m.ID(), "")
 		if err != nil {
 			return err
 		}
@@ -294,32 +530,46 @@ func (ms *Migrations) runBatch(
 			statements = append(statements, spanner.Statement{SQL: s.Sql})
 		}
 
-		db, err := ms.Database(ctx)
-		if err != nil {
-			return err
-		}
-
-		_, err = db.ReadWriteTransaction(ctx, func(ctx context.Context, tx *spanner.ReadWriteTransaction) error {
-			_, err := tx.BatchUpdate(ctx, statements)
-			return err
-		})
+		_, err := ms.executor().BatchUpdate(ctx, statements)
 		if err != nil {
 			return err
 		}
 	case jimmyv1.Type_PARTITIONED_DML:
-		db, err := ms.Database(ctx)
-		if err != nil {
-			return err
-		}
-
 		for _, s := range batch.Statements {
-			_, err := db.PartitionedUpdate(ctx, spanner.Statement{
+			_, err := ms.executor().PartitionedUpdate(ctx, spanner.Statement{
 				SQL: s.Sql,
 			})
 			if err != nil {
 				return err
 			}
 		}
+	case jimmyv1.Type_BATCHED_DML:
+		for _, s := range batch.Statements {
+			err := ms.runBatchedDML(ctx, m, s, o.onProgress)
+			if err != nil {
+				return err
+			}
+		}
+	case jimmyv1.Type_LOAD:
+		for _, s := range batch.Statements {
+			err := ms.runLoad(ctx, m, s)
+			if err != nil {
+				return err
+			}
+		}
+	case jimmyv1.Type_ASSERT:
+		for _, s := range batch.Statements {
+			result, found, err := ms.executor().QueryBool(ctx, spanner.Statement{
+				SQL: s.Sql,
+			})
+			if err != nil {
+				return fmt.Errorf("migration %d assertion: %w", m.ID(), err)
+			}
+
+			if found && !(result.Valid && result.Bool) {
+				return fmt.Errorf("migration %d %w: %s", m.ID(), ErrAssertion, strings.TrimSpace(s.Sql))
+			}
+		}
 	default:
 		return fmt.Errorf("unhandled type %s", batch.Statements[0].String())
 	}
@@ -327,18 +577,71 @@ func (ms *Migrations) runBatch(
 	return nil
 }
 
-func (ms *Migrations) completeMigration(ctx context.Context, id int) error {
-	db, err := ms.Database(ctx)
+// submitDDL submits a DDL batch and records the operation name, so an
+// interrupted upgrade can wait for it on the next run.
+func (ms *Migrations) submitDDL(
+	ctx context.Context,
+	m *Migration,
+	batch *Batch,
+) (string, error) {
+	var statements []string
+
+	for _, s := range batch.Statements {
+		statements = append(statements, s.Sql)
+	}
+
+	var protoDescriptors []byte
+
+	// attach proto descriptors
+	if batch.FileDescriptorSet != "" {
+		id := batch.FileDescriptorSet
+
+		var fileDescriptorSet *descriptorpb.FileDescriptorSet
+
+		if len(m.data.FileDescriptorSets) > 0 {
+			fileDescriptorSet = m.data.FileDescriptorSets[id]
+		}
+
+		if fileDescriptorSet == nil {
+			return "", fmt.Errorf("file descriptor set %q not found", id)
+		}
+
+		b, err := proto.Marshal(fileDescriptorSet)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal %q file descriptor set", id)
+		}
+
+		protoDescriptors = b
+	}
+
+	operation, err := ms.executor().UpdateDDL(ctx, statements, protoDescriptors)
 	if err != nil {
-		return err
+		return "", err
+	}
+
+	err = ms.setOperation(ctx, m.ID(), operation)
+	if err != nil {
+		return "", err
 	}
 
-	_, err = db.Apply(ctx, []*spanner.Mutation{
-		spanner.Update(
-			ms.Config.Table,
-			[]string{"id", "complete_time"},
-			[]any{int64(id), spanner.CommitTimestamp},
-		),
+	return operation, nil
+}
+
+// setOperation records the DDL operation a migration is waiting for, or
+// clears it when the operation is empty.
+func (ms *Migrations) setOperation(ctx context.Context, id int, operation string) error {
+	err := ms.executor().UpdateMigration(ctx, ms.Config.Table, id, map[string]any{
+		"operation": spanner.NullString{StringVal: operation, Valid: operation != ""},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record operation: %w", err)
+	}
+
+	return nil
+}
+
+func (ms *Migrations) completeMigration(ctx context.Context, id int) error {
+	return ms.executor().UpdateMigration(ctx, ms.Config.Table, id, map[string]any{
+		"complete_time": spanner.CommitTimestamp,
 	})
-	return err
 }
@@ -0,0 +1,80 @@
+package migrations
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
+)
+
+func TestPending(t *testing.T) {
+	ms := New("")
+
+	add := func(id int, squashID *int64) {
+		ms.setMigration(newMigration(ms, id, "", &jimmyv1.Migration{SquashId: squashID}))
+	}
+
+	add(20260101000000, nil)
+	add(20260102000000, nil)
+	add(20260103000000, nil)
+	add(20260104000000, Ref[int64](20260102000000))
+	add(20260105000000, nil)
+
+	pending := func(applied ...int) []int {
+		sequence, err := ms.pending(func(id int) bool {
+			for _, appliedID := range applied {
+				if id == appliedID {
+					return true
+				}
+			}
+			return false
+		})
+		require.NoError(t, err)
+
+		var ids []int
+		for _, m := range sequence {
+			ids = append(ids, m.ID())
+		}
+		return ids
+	}
+
+	// new database jumps to the squash migration
+	require.Equal(t, []int{20260101000000, 20260104000000, 20260105000000}, pending())
+
+	// squash ID applied, so the squash migration is skipped
+	require.Equal(t, []int{20260103000000, 20260105000000}, pending(20260101000000, 20260102000000))
+
+	// squash migration applied, so the squashed migrations are skipped
+	require.Equal(t, []int{20260105000000}, pending(20260101000000, 20260104000000))
+
+	// out of order migration
+	require.Equal(t, []int{20260101000000}, pending(20260104000000, 20260105000000))
+
+	sequence, err := ms.sequence(20260103000000)
+	require.NoError(t, err)
+	require.Len(t, sequence, 1)
+	require.Equal(t, 20260105000000, sequence[0].ID())
+}
+
+func TestNextID(t *testing.T) {
+	ms := New("")
+	require.Equal(t, 1, ms.nextID())
+
+	ms.setMigration(newMigration(ms, 1, "", &jimmyv1.Migration{}))
+	require.Equal(t, 2, ms.nextID())
+
+	ms.Config.IdScheme = jimmyv1.IdScheme_TIMESTAMP
+
+	before, err := strconv.Atoi(time.Now().UTC().Format("20060102150405"))
+	require.NoError(t, err)
+
+	id := ms.nextID()
+	require.GreaterOrEqual(t, id, before)
+	require.Equal(t, strconv.Itoa(id)+"_test.yaml", ms.fileName(id, "test"))
+
+	ms.setMigration(newMigration(ms, id, "", &jimmyv1.Migration{}))
+	require.Greater(t, ms.nextID(), id)
+}
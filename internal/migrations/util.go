@@ -21,6 +21,7 @@ var (
 	dmlPartitionedPrefix    = regexp.MustCompile(`^(?i)\W*(DELETE|UPDATE)`)
 	dmlPrefix               = regexp.MustCompile(`^(?i)\W*INSERT`)
 	dmlProtoBundle          = regexp.MustCompile(`^(?i)\W*(CREATE|ALTER)\W+PROTO\W+BUNDLE`)
+	dmlDropProtoBundle      = regexp.MustCompile(`^(?i)\W*DROP\W+PROTO\W+BUNDLE`)
 )
 
 func Ref[T any](v T) *T {
@@ -79,6 +80,67 @@ func isProtoDDL(sql string) bool {
 	return dmlProtoBundle.MatchString(sql)
 }
 
+// splitStatements splits a SQL script into statements, dropping comments
+// and empty statements.
+func splitStatements(sql string) []string {
+	var statements []string
+	var b strings.Builder
+
+	flush := func() {
+		if s := strings.TrimSpace(b.String()); s != "" {
+			statements = append(statements, s)
+		}
+		b.Reset()
+	}
+
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+
+		switch {
+		case c == ';':
+			flush()
+		case c == '#' || strings.HasPrefix(sql[i:], "--"):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end == -1 {
+				i = len(sql)
+			} else {
+				i += end - 1
+			}
+		case strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end == -1 {
+				i = len(sql)
+			} else {
+				i += end + 3
+			}
+			b.WriteByte(' ')
+		case c == '"' || c == '\'' || c == '`':
+			quote := string(c)
+			if strings.HasPrefix(sql[i:], strings.Repeat(quote, 3)) {
+				quote = strings.Repeat(quote, 3)
+			}
+
+			end := i + len(quote)
+			for end < len(sql) && !strings.HasPrefix(sql[end:], quote) {
+				if sql[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			end = min(end+len(quote), len(sql))
+
+			b.WriteString(sql[i:end])
+			i = end - 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	flush()
+
+	return statements
+}
+
 func checkFile(path, fileType string) error {
 	fileInfo, err := os.Stat(path)
 	if err != nil {
@@ -95,3 +95,52 @@ func TestIsProtoDDL(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitStatements(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		SQL      string
+		Expected []string
+	}{
+		{
+			Name:     "Empty",
+			SQL:      " \n;\n",
+			Expected: nil,
+		},
+		{
+			Name:     "Single",
+			SQL:      "CREATE TABLE test (id INT64) PRIMARY KEY (id)",
+			Expected: []string{"CREATE TABLE test (id INT64) PRIMARY KEY (id)"},
+		},
+		{
+			Name: "Multiple",
+			SQL:  "CREATE TABLE a (id INT64) PRIMARY KEY (id);\n\nCREATE INDEX idx_a ON a (id);\n",
+			Expected: []string{
+				"CREATE TABLE a (id INT64) PRIMARY KEY (id)",
+				"CREATE INDEX idx_a ON a (id)",
+			},
+		},
+		{
+			Name: "Comments",
+			SQL:  "-- first;\nSELECT 1; # second;\n/* third; */ SELECT 2",
+			Expected: []string{
+				"SELECT 1",
+				"SELECT 2",
+			},
+		},
+		{
+			Name: "Quotes",
+			SQL:  `SELECT 'a;b', "c;d", ` + "`e;f`" + `, '''g;h''', 'i\';j'; SELECT 2`,
+			Expected: []string{
+				`SELECT 'a;b', "c;d", ` + "`e;f`" + `, '''g;h''', 'i\';j'`,
+				"SELECT 2",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			require.Equal(t, tc.Expected, splitStatements(tc.SQL))
+		})
+	}
+}
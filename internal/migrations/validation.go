@@ -3,8 +3,14 @@ package migrations
 import (
 	"errors"
 	"fmt"
+	"maps"
+	"os"
+	"slices"
+
+	"cloud.google.com/go/spanner/admin/database/apiv1/databasepb"
 
 	"github.com/silas/jimmy/internal/constants"
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
 )
 
 func (ms *Migrations) Validate() error {
@@ -12,38 +18,191 @@ func (ms *Migrations) Validate() error {
 		return errors.New("must be initialized using New")
 	}
 
+	problems := slices.Clone(ms.problems)
+
 	if ms.Path == "" {
-		return fmt.Errorf("%q path required", constants.ConfigFile)
+		problems = append(problems, fmt.Errorf("%q path required", constants.ConfigFile))
 	}
 
 	if ms.Config.ProjectId == "" {
-		return errors.New("project ID required")
+		problems = append(problems, errors.New("project ID required"))
 	}
 
 	if ms.Config.InstanceId == "" {
-		return errors.New("instance ID required")
+		problems = append(problems, errors.New("instance ID required"))
 	}
 
 	if ms.Config.DatabaseId == "" {
-		return errors.New("database ID required")
+		problems = append(problems, errors.New("database ID required"))
+	}
+
+	if err := checkDialect(ms.Config.GetCreateDatabase()); err != nil {
+		problems = append(problems, err)
 	}
 
+	problems = append(problems, validateTemplates(ms.Config.Templates)...)
+
+	if ms.Env != "" {
+		if err := ms.checkEnvs(ms.Env); err != nil {
+			problems = append(problems, err)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(ms.Config.GetTargets())) {
+		if env := ms.Config.Targets[name].GetEnv(); env != "" {
+			if err := ms.checkEnvs(env); err != nil {
+				problems = append(problems, fmt.Errorf("%q target: %w", name, err))
+			}
+		}
+	}
+
+	problems = append(problems, ms.validateMigrations()...)
+
+	return errors.Join(problems...)
+}
+
+// checkDialect rejects database dialects other than GoogleSQL, which the
+// migration and seed queries are written in.
+func checkDialect(create *jimmyv1.CreateDatabase) error {
+	if dialect := create.GetDialect(); dialect == databasepb.DatabaseDialect_POSTGRESQL.String() {
+		return fmt.Errorf("create_database dialect %s isn't supported, only %s databases can be migrated",
+			dialect, databasepb.DatabaseDialect_GOOGLE_STANDARD_SQL)
+	}
+
+	return nil
+}
+
+func validateTemplates(templates map[string]*jimmyv1.Template) []error {
+	var problems []error
+
 	var defaultTemplateID string
-	for templateID, template := range ms.Config.Templates {
-		if !template.GetDefault() {
+	for _, templateID := range slices.Sorted(maps.Keys(templates)) {
+		if !templates[templateID].GetDefault() {
 			continue
 		}
 
 		if defaultTemplateID != "" {
-			return fmt.Errorf(
+			problems = append(problems, fmt.Errorf(
 				"%q and %q can't both be marked as the default template",
 				defaultTemplateID,
 				templateID,
-			)
+			))
+			continue
 		}
 
 		defaultTemplateID = templateID
 	}
 
-	return nil
+	return problems
+}
+
+func (ms *Migrations) conflictProblems() []error {
+	var problems []error
+
+	for _, m := range ms.conflicts {
+		problems = append(problems, fmt.Errorf(
+			"migration %d has conflicting migration files %q and %q",
+			m.ID(), m.FileName(), ms.migrations[m.ID()].FileName(),
+		))
+	}
+
+	return problems
+}
+
+func (ms *Migrations) validateMigrations() []error {
+	problems := ms.conflictProblems()
+
+	ids := slices.Sorted(maps.Keys(ms.migrations))
+
+	if !ms.Config.GetAllowIdGaps() && ms.Config.GetIdScheme() == jimmyv1.IdScheme_SEQUENTIAL {
+		previousID := 0
+
+		for _, id := range ids {
+			if id == previousID+2 {
+				problems = append(problems, fmt.Errorf("migration %d is missing", previousID+1))
+			} else if id > previousID+2 {
+				problems = append(problems, fmt.Errorf("migrations %d to %d are missing", previousID+1, id-1))
+			}
+
+			previousID = id
+		}
+	}
+
+	for _, id := range ids {
+		m := ms.migrations[id]
+
+		for i, s := range m.data.GetUpgrade() {
+			if err := ms.checkEnvs(append(slices.Clone(s.Envs), s.ExcludeEnvs...)...); err != nil {
+				problems = append(problems, fmt.Errorf("migration %d upgrade statement %d: %w", id, i+1, err))
+			}
+
+			if s.Batched != nil && s.Type != jimmyv1.Type_BATCHED_DML {
+				problems = append(problems, fmt.Errorf(
+					"migration %d upgrade statement %d has batched options but isn't %s",
+					id, i+1, jimmyv1.Type_BATCHED_DML,
+				))
+			}
+
+			switch {
+			case s.Load != nil && s.Type != jimmyv1.Type_LOAD:
+				problems = append(problems, fmt.Errorf(
+					"migration %d upgrade statement %d has load options but isn't %s",
+					id, i+1, jimmyv1.Type_LOAD,
+				))
+			case s.Load == nil && s.Type == jimmyv1.Type_LOAD:
+				problems = append(problems, fmt.Errorf(
+					"migration %d upgrade statement %d is missing load options",
+					id, i+1,
+				))
+			case s.Load != nil:
+				if _, err := os.Stat(m.loadPath(s)); err != nil {
+					problems = append(problems, fmt.Errorf(
+						"migration %d upgrade statement %d load file %q not found",
+						id, i+1, s.Load.GetFile(),
+					))
+				}
+			}
+
+			if s.FileDescriptorSet == nil {
+				continue
+			}
+
+			if _, found := m.data.GetFileDescriptorSets()[s.GetFileDescriptorSet()]; !found {
+				problems = append(problems, fmt.Errorf(
+					"migration %d upgrade statement %d references unknown file descriptor set %q",
+					id, i+1, s.GetFileDescriptorSet(),
+				))
+			}
+		}
+
+		// a squash ID of zero marks a bootstrap migration
+		squashID, found := m.SquashID()
+		if !found || squashID == 0 {
+			continue
+		}
+
+		sm := ms.migrations[squashID]
+
+		switch {
+		case squashID >= id:
+			problems = append(problems, fmt.Errorf(
+				"migration %d squash ID %d must be lower than the migration ID",
+				id, squashID,
+			))
+		case sm == nil:
+			problems = append(problems, fmt.Errorf(
+				"migration %d squash ID %d not found",
+				id, squashID,
+			))
+		default:
+			if _, found := sm.SquashID(); found {
+				problems = append(problems, fmt.Errorf(
+					"migration %d squash ID %d can't reference squash migration",
+					id, squashID,
+				))
+			}
+		}
+	}
+
+	return problems
 }
@@ -0,0 +1,113 @@
+package migrations
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
+)
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+
+	write := func(name, content string) {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+		require.NoError(t, err)
+	}
+
+	write(".jimmy.yaml", "path: "+dir+"\nproject_id: test\ninstance_id: test\ndatabase_id: test\ntable: migrations\n")
+	write("00001_init.yaml", "upgrade:\n  - sql: CREATE TABLE a (id INT64) PRIMARY KEY (id)\n  - sql: DELETE FROM a WHERE true\n    type: DML\n    batched:\n      size: 10\n")
+	write("00002_proto.yaml", "upgrade:\n  - sql: CREATE PROTO BUNDLE (test.v1.User)\n    file_descriptor_set: missing\n")
+	write("00003_squash.yaml", "upgrade:\n  - sql: SELECT 1\nsquash_id: 4\n")
+	write("00005_a.yaml", "upgrade:\n  - sql: SELECT 1\nsquash_id: 3\n")
+	write("00005_b.yaml", "upgrade:\n  - sql: SELECT 1\n")
+	write("00006_Bad-Slug.yaml", "upgrade:\n  - sql: SELECT 1\n")
+	write("notes.yaml", "")
+	write("README.md", "")
+
+	// loading for an upgrade fails on invalid and conflicting files
+	err := New(filepath.Join(dir, ".jimmy.yaml")).Load(context.Background())
+	require.Error(t, err)
+	require.Equal(t, []string{
+		`"00006_Bad-Slug.yaml" isn't a valid migration file name, expected <id>_<slug>.yaml`,
+		`"notes.yaml" isn't a valid migration file name, expected <id>_<slug>.yaml`,
+		`migration 5 has conflicting migration files "00005_b.yaml" and "00005_a.yaml"`,
+	}, strings.Split(err.Error(), "\n"))
+
+	ms := New(filepath.Join(dir, ".jimmy.yaml"))
+
+	err = ms.LoadAll(context.Background())
+	require.NoError(t, err)
+
+	err = ms.Validate()
+	require.Error(t, err)
+	require.Equal(t, []string{
+		`"00006_Bad-Slug.yaml" isn't a valid migration file name, expected <id>_<slug>.yaml`,
+		`"notes.yaml" isn't a valid migration file name, expected <id>_<slug>.yaml`,
+		`migration 5 has conflicting migration files "00005_b.yaml" and "00005_a.yaml"`,
+		`migration 4 is missing`,
+		`migration 1 upgrade statement 2 has batched options but isn't BATCHED_DML`,
+		`migration 2 upgrade statement 1 references unknown file descriptor set "missing"`,
+		`migration 3 squash ID 4 must be lower than the migration ID`,
+		`migration 5 squash ID 3 can't reference squash migration`,
+	}, strings.Split(err.Error(), "\n"))
+
+	ms.Config.AllowIdGaps = true
+	ms.problems = nil
+	ms.conflicts = nil
+	ms.migrations[1].data.Upgrade[1].Type = jimmyv1.Type_BATCHED_DML
+	delete(ms.migrations, 2)
+	delete(ms.migrations, 3)
+	delete(ms.migrations, 5)
+
+	require.NoError(t, ms.Validate())
+}
+
+func TestValidate_bootstrap(t *testing.T) {
+	dir := t.TempDir()
+
+	write := func(name, content string) {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+		require.NoError(t, err)
+	}
+
+	write(".jimmy.yaml", "path: "+dir+"\nproject_id: test\ninstance_id: test\ndatabase_id: test\ntable: migrations\n")
+	write("00001_bootstrap.yaml", "upgrade:\n  - sql: CREATE TABLE a (id INT64) PRIMARY KEY (id)\nsquash_id: 0\n")
+	write("00002_add.yaml", "upgrade:\n  - sql: CREATE TABLE b (id INT64) PRIMARY KEY (id)\n")
+
+	ms := New(filepath.Join(dir, ".jimmy.yaml"))
+
+	err := ms.Load(context.Background())
+	require.NoError(t, err)
+
+	squashID, found := ms.migrations[1].SquashID()
+	require.True(t, found)
+	require.Zero(t, squashID)
+
+	require.NoError(t, ms.Validate())
+}
+
+func TestValidate_dialect(t *testing.T) {
+	dir := t.TempDir()
+
+	err := os.WriteFile(
+		filepath.Join(dir, ".jimmy.yaml"),
+		[]byte("path: "+dir+"\nproject_id: test\ninstance_id: test\ndatabase_id: test\ntable: migrations\ncreate_database:\n  dialect: POSTGRESQL\n"),
+		0644,
+	)
+	require.NoError(t, err)
+
+	ms := New(filepath.Join(dir, ".jimmy.yaml"))
+	require.NoError(t, ms.Load(context.Background()))
+
+	err = ms.Validate()
+	require.EqualError(t, err, "create_database dialect POSTGRESQL isn't supported, only GOOGLE_STANDARD_SQL databases can be migrated")
+
+	ms.Config.CreateDatabase.Dialect = "GOOGLE_STANDARD_SQL"
+	require.NoError(t, ms.Validate())
+}
@@ -0,0 +1,327 @@
+package migrations
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"cloud.google.com/go/spanner"
+	"gopkg.in/yaml.v3"
+)
+
+// columnTypes returns the Spanner types of a table's columns keyed by
+// column name.
+func (ms *Migrations) columnTypes(ctx context.Context, table string) (map[string]string, error) {
+	columns, err := ms.executor().ColumnTypes(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table %q not found", table)
+	}
+
+	return columns, nil
+}
+
+// coerceValue converts a value decoded from a YAML, JSON or CSV file into
+// a value that can be written to a column of the given Spanner type.
+//
+// Strings are parsed for non-string columns, so CSV cells can be used for
+// any type, with empty strings written as NULL.
+func coerceValue(spannerType string, v any) (any, error) {
+	spannerType = strings.ToUpper(strings.TrimSpace(spannerType))
+
+	if elemType, found := strings.CutPrefix(spannerType, "ARRAY<"); found {
+		elemType = strings.TrimSuffix(elemType, ">")
+
+		if s, ok := v.(string); ok {
+			if s == "" {
+				return coerceArray(elemType, nil)
+			}
+
+			err := yaml.Unmarshal([]byte(s), &v)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse array %q: %w", s, err)
+			}
+		}
+
+		if v == nil {
+			return coerceArray(elemType, nil)
+		}
+
+		values, ok := v.([]any)
+		if !ok {
+			return nil, fmt.Errorf("expected array for %s, got %T", spannerType, v)
+		}
+
+		return coerceArray(elemType, values)
+	}
+
+	baseType, _, _ := strings.Cut(spannerType, "(")
+
+	switch baseType {
+	case "STRING":
+		return coerceString(v)
+	case "INT64":
+		return coerceInt64(v)
+	case "FLOAT64":
+		return coerceFloat64(v)
+	case "FLOAT32":
+		f, err := coerceFloat64(v)
+		if err != nil {
+			return nil, err
+		}
+		return spanner.NullFloat32{Float32: float32(f.Float64), Valid: f.Valid}, nil
+	case "BOOL":
+		return coerceBool(v)
+	case "BYTES":
+		return coerceBytes(v)
+	case "DATE":
+		return coerceDate(v)
+	case "TIMESTAMP":
+		return coerceTimestamp(v)
+	case "NUMERIC":
+		return coerceNumeric(v)
+	case "JSON":
+		return coerceJSON(v)
+	default:
+		return nil, fmt.Errorf("unsupported column type %s", spannerType)
+	}
+}
+
+func coerceArray(elemType string, values []any) (any, error) {
+	baseType, _, _ := strings.Cut(elemType, "(")
+
+	switch baseType {
+	case "STRING":
+		return coerceSlice(values, coerceString)
+	case "INT64":
+		return coerceSlice(values, coerceInt64)
+	case "FLOAT64":
+		return coerceSlice(values, coerceFloat64)
+	case "BOOL":
+		return coerceSlice(values, coerceBool)
+	case "BYTES":
+		return coerceSlice(values, coerceBytes)
+	case "DATE":
+		return coerceSlice(values, coerceDate)
+	case "TIMESTAMP":
+		return coerceSlice(values, coerceTimestamp)
+	case "NUMERIC":
+		return coerceSlice(values, coerceNumeric)
+	case "JSON":
+		return coerceSlice(values, coerceJSON)
+	default:
+		return nil, fmt.Errorf("unsupported column type ARRAY<%s>", elemType)
+	}
+}
+
+func coerceSlice[T any](values []any, coerce func(v any) (T, error)) ([]T, error) {
+	if values == nil {
+		return nil, nil
+	}
+
+	result := make([]T, 0, len(values))
+
+	for _, v := range values {
+		value, err := coerce(v)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, value)
+	}
+
+	return result, nil
+}
+
+func coerceString(v any) (spanner.NullString, error) {
+	switch v := v.(type) {
+	case nil:
+		return spanner.NullString{}, nil
+	case string:
+		return spanner.NullString{StringVal: v, Valid: true}, nil
+	case time.Time:
+		return spanner.NullString{StringVal: v.Format(time.RFC3339Nano), Valid: true}, nil
+	case int, int64, float64, bool:
+		return spanner.NullString{StringVal: fmt.Sprint(v), Valid: true}, nil
+	default:
+		return spanner.NullString{}, fmt.Errorf("expected string, got %T", v)
+	}
+}
+
+func coerceInt64(v any) (spanner.NullInt64, error) {
+	switch v := v.(type) {
+	case nil:
+		return spanner.NullInt64{}, nil
+	case int:
+		return spanner.NullInt64{Int64: int64(v), Valid: true}, nil
+	case int64:
+		return spanner.NullInt64{Int64: v, Valid: true}, nil
+	case float64:
+		if v != math.Trunc(v) {
+			return spanner.NullInt64{}, fmt.Errorf("expected integer, got %v", v)
+		}
+		return spanner.NullInt64{Int64: int64(v), Valid: true}, nil
+	case string:
+		if v == "" {
+			return spanner.NullInt64{}, nil
+		}
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return spanner.NullInt64{}, fmt.Errorf("expected integer, got %q", v)
+		}
+		return spanner.NullInt64{Int64: i, Valid: true}, nil
+	default:
+		return spanner.NullInt64{}, fmt.Errorf("expected integer, got %T", v)
+	}
+}
+
+func coerceFloat64(v any) (spanner.NullFloat64, error) {
+	switch v := v.(type) {
+	case nil:
+		return spanner.NullFloat64{}, nil
+	case int:
+		return spanner.NullFloat64{Float64: float64(v), Valid: true}, nil
+	case int64:
+		return spanner.NullFloat64{Float64: float64(v), Valid: true}, nil
+	case float64:
+		return spanner.NullFloat64{Float64: v, Valid: true}, nil
+	case string:
+		if v == "" {
+			return spanner.NullFloat64{}, nil
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return spanner.NullFloat64{}, fmt.Errorf("expected number, got %q", v)
+		}
+		return spanner.NullFloat64{Float64: f, Valid: true}, nil
+	default:
+		return spanner.NullFloat64{}, fmt.Errorf("expected number, got %T", v)
+	}
+}
+
+func coerceBool(v any) (spanner.NullBool, error) {
+	switch v := v.(type) {
+	case nil:
+		return spanner.NullBool{}, nil
+	case bool:
+		return spanner.NullBool{Bool: v, Valid: true}, nil
+	case string:
+		if v == "" {
+			return spanner.NullBool{}, nil
+		}
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return spanner.NullBool{}, fmt.Errorf("expected boolean, got %q", v)
+		}
+		return spanner.NullBool{Bool: b, Valid: true}, nil
+	default:
+		return spanner.NullBool{}, fmt.Errorf("expected boolean, got %T", v)
+	}
+}
+
+func coerceBytes(v any) ([]byte, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return v, nil
+	case string:
+		b, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("expected base64 encoded bytes: %w", err)
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("expected bytes, got %T", v)
+	}
+}
+
+func coerceDate(v any) (spanner.NullDate, error) {
+	switch v := v.(type) {
+	case nil:
+		return spanner.NullDate{}, nil
+	case time.Time:
+		return spanner.NullDate{Date: civil.DateOf(v), Valid: true}, nil
+	case string:
+		if v == "" {
+			return spanner.NullDate{}, nil
+		}
+		d, err := civil.ParseDate(v)
+		if err != nil {
+			return spanner.NullDate{}, fmt.Errorf("expected date, got %q", v)
+		}
+		return spanner.NullDate{Date: d, Valid: true}, nil
+	default:
+		return spanner.NullDate{}, fmt.Errorf("expected date, got %T", v)
+	}
+}
+
+func coerceTimestamp(v any) (spanner.NullTime, error) {
+	switch v := v.(type) {
+	case nil:
+		return spanner.NullTime{}, nil
+	case time.Time:
+		return spanner.NullTime{Time: v, Valid: true}, nil
+	case string:
+		if v == "" {
+			return spanner.NullTime{}, nil
+		}
+		if strings.EqualFold(v, "PENDING_COMMIT_TIMESTAMP()") {
+			return spanner.NullTime{Time: spanner.CommitTimestamp, Valid: true}, nil
+		}
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return spanner.NullTime{}, fmt.Errorf("expected RFC 3339 timestamp, got %q", v)
+		}
+		return spanner.NullTime{Time: t, Valid: true}, nil
+	default:
+		return spanner.NullTime{}, fmt.Errorf("expected timestamp, got %T", v)
+	}
+}
+
+func coerceNumeric(v any) (spanner.NullNumeric, error) {
+	switch v := v.(type) {
+	case nil:
+		return spanner.NullNumeric{}, nil
+	case int, int64, float64, string:
+		s := fmt.Sprint(v)
+		if s == "" {
+			return spanner.NullNumeric{}, nil
+		}
+		r, ok := new(big.Rat).SetString(s)
+		if !ok {
+			return spanner.NullNumeric{}, fmt.Errorf("expected numeric, got %q", s)
+		}
+		return spanner.NullNumeric{Numeric: *r, Valid: true}, nil
+	default:
+		return spanner.NullNumeric{}, fmt.Errorf("expected numeric, got %T", v)
+	}
+}
+
+func coerceJSON(v any) (spanner.NullJSON, error) {
+	if s, ok := v.(string); ok {
+		if s == "" {
+			return spanner.NullJSON{}, nil
+		}
+
+		err := yaml.Unmarshal([]byte(s), &v)
+		if err != nil {
+			return spanner.NullJSON{}, fmt.Errorf("failed to parse JSON %q: %w", s, err)
+		}
+	}
+
+	if v == nil {
+		return spanner.NullJSON{}, nil
+	}
+
+	return spanner.NullJSON{Value: v, Valid: true}, nil
+}
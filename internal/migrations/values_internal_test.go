@@ -0,0 +1,67 @@
+package migrations
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"cloud.google.com/go/spanner"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoerceValue(t *testing.T) {
+	testCases := []struct {
+		Type  string
+		Value any
+		Want  any
+		Error string
+	}{
+		{Type: "STRING(MAX)", Value: "one", Want: spanner.NullString{StringVal: "one", Valid: true}},
+		{Type: "STRING(10)", Value: 1, Want: spanner.NullString{StringVal: "1", Valid: true}},
+		{Type: "STRING(MAX)", Value: nil, Want: spanner.NullString{}},
+		{Type: "INT64", Value: 1, Want: spanner.NullInt64{Int64: 1, Valid: true}},
+		{Type: "INT64", Value: "2", Want: spanner.NullInt64{Int64: 2, Valid: true}},
+		{Type: "INT64", Value: "", Want: spanner.NullInt64{}},
+		{Type: "INT64", Value: 1.5, Error: "expected integer, got 1.5"},
+		{Type: "FLOAT64", Value: "1.5", Want: spanner.NullFloat64{Float64: 1.5, Valid: true}},
+		{Type: "FLOAT32", Value: 2, Want: spanner.NullFloat32{Float32: 2, Valid: true}},
+		{Type: "BOOL", Value: "true", Want: spanner.NullBool{Bool: true, Valid: true}},
+		{Type: "BYTES(MAX)", Value: "aGk=", Want: []byte("hi")},
+		{Type: "DATE", Value: "2026-10-18", Want: spanner.NullDate{Date: civil.Date{Year: 2026, Month: 10, Day: 18}, Valid: true}},
+		{
+			Type:  "TIMESTAMP",
+			Value: "2026-10-18T15:30:00Z",
+			Want:  spanner.NullTime{Time: time.Date(2026, 10, 18, 15, 30, 0, 0, time.UTC), Valid: true},
+		},
+		{Type: "TIMESTAMP", Value: "pending_commit_timestamp()", Want: spanner.NullTime{Time: spanner.CommitTimestamp, Valid: true}},
+		{Type: "NUMERIC", Value: "1.25", Want: spanner.NullNumeric{Numeric: *big.NewRat(5, 4), Valid: true}},
+		{Type: "JSON", Value: `{"a": 1}`, Want: spanner.NullJSON{Value: map[string]any{"a": 1}, Valid: true}},
+		{Type: "JSON", Value: map[string]any{"a": 1}, Want: spanner.NullJSON{Value: map[string]any{"a": 1}, Valid: true}},
+		{
+			Type:  "ARRAY<STRING(MAX)>",
+			Value: []any{"a", nil},
+			Want:  []spanner.NullString{{StringVal: "a", Valid: true}, {}},
+		},
+		{
+			Type:  "ARRAY<INT64>",
+			Value: "[1, 2]",
+			Want:  []spanner.NullInt64{{Int64: 1, Valid: true}, {Int64: 2, Valid: true}},
+		},
+		{Type: "ARRAY<INT64>", Value: "", Want: []spanner.NullInt64(nil)},
+		{Type: "INTERVAL", Value: "1", Error: "unsupported column type INTERVAL"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Type, func(t *testing.T) {
+			v, err := coerceValue(tc.Type, tc.Value)
+			if tc.Error != "" {
+				require.EqualError(t, err, tc.Error)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.Want, v)
+		})
+	}
+}
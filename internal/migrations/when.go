@@ -0,0 +1,53 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+
+	"cloud.google.com/go/spanner"
+
+	jimmyv1 "github.com/silas/jimmy/internal/pb/jimmy/v1"
+)
+
+// evaluateWhen runs the when query of a statement, returning whether the
+// statement should run.
+func (ms *Migrations) evaluateWhen(ctx context.Context, s *jimmyv1.Statement) (bool, error) {
+	if s.When == nil {
+		return true, nil
+	}
+
+	result, _, err := ms.executor().QueryBool(ctx, spanner.Statement{
+		SQL: s.GetWhen(),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return result.Valid && result.Bool, nil
+}
+
+// readBool reads the single boolean value returned by a query, returning
+// whether a row was found.
+func readBool(iter *spanner.RowIterator) (spanner.NullBool, bool, error) {
+	var result spanner.NullBool
+	var rows int
+
+	err := iter.Do(func(r *spanner.Row) error {
+		rows++
+
+		if rows > 1 {
+			return errors.New("query returned more than one row")
+		}
+
+		if r.Size() != 1 {
+			return errors.New("query must return a single column")
+		}
+
+		return r.Column(0, &result)
+	})
+	if err != nil {
+		return spanner.NullBool{}, false, err
+	}
+
+	return result, rows > 0, nil
+}
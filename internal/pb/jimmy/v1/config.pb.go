@@ -21,6 +21,57 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type IdScheme int32
+
+const (
+	// Sequential migration IDs (00001, 00002, ...).
+	IdScheme_SEQUENTIAL IdScheme = 0
+	// UTC timestamp migration IDs (20060102150405).
+	//
+	// Migrations with IDs lower than the latest applied migration
+	// that haven't been applied are run in ID order.
+	IdScheme_TIMESTAMP IdScheme = 1
+)
+
+// Enum value maps for IdScheme.
+var (
+	IdScheme_name = map[int32]string{
+		0: "SEQUENTIAL",
+		1: "TIMESTAMP",
+	}
+	IdScheme_value = map[string]int32{
+		"SEQUENTIAL": 0,
+		"TIMESTAMP":  1,
+	}
+)
+
+func (x IdScheme) Enum() *IdScheme {
+	p := new(IdScheme)
+	*p = x
+	return p
+}
+
+func (x IdScheme) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (IdScheme) Descriptor() protoreflect.EnumDescriptor {
+	return file_jimmy_v1_config_proto_enumTypes[0].Descriptor()
+}
+
+func (IdScheme) Type() protoreflect.EnumType {
+	return &file_jimmy_v1_config_proto_enumTypes[0]
+}
+
+func (x IdScheme) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use IdScheme.Descriptor instead.
+func (IdScheme) EnumDescriptor() ([]byte, []int) {
+	return file_jimmy_v1_config_proto_rawDescGZIP(), []int{0}
+}
+
 // The .jimmy.yml configuration file.
 type Config struct {
 	state         protoimpl.MessageState
@@ -39,6 +90,32 @@ type Config struct {
 	Table string `protobuf:"bytes,5,opt,name=table,proto3" json:"table,omitempty"`
 	// The custom templates.
 	Templates map[string]*Template `protobuf:"bytes,6,rep,name=templates,proto3" json:"templates,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The location of the schema dump file.
+	//
+	// Protocol Buffers file descriptors are written alongside the
+	// schema using the same name with a .pb extension.
+	Schema string `protobuf:"bytes,7,opt,name=schema,proto3" json:"schema,omitempty"`
+	// Whether gaps between migration IDs are allowed.
+	AllowIdGaps bool `protobuf:"varint,8,opt,name=allow_id_gaps,json=allowIdGaps,proto3" json:"allow_id_gaps,omitempty"`
+	// The scheme used to allocate new migration IDs.
+	IdScheme IdScheme `protobuf:"varint,9,opt,name=id_scheme,json=idScheme,proto3,enum=jimmy.v1.IdScheme" json:"id_scheme,omitempty"`
+	// The location of the seeds directory.
+	//
+	// Seed files in the directory are written for all environments, and
+	// seed files in a subdirectory named after an environment (for
+	// example emulator or a user-defined environment) are only written
+	// for that environment.
+	Seeds string `protobuf:"bytes,10,opt,name=seeds,proto3" json:"seeds,omitempty"`
+	// The user-defined environments (for example dev, staging and prod).
+	Environments []string `protobuf:"bytes,11,rep,name=environments,proto3" json:"environments,omitempty"`
+	// The named targets, selected with the --target flag.
+	Targets map[string]*Target `protobuf:"bytes,12,rep,name=targets,proto3" json:"targets,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The database options applied by upgrades.
+	DatabaseOptions *DatabaseOptions `protobuf:"bytes,13,opt,name=database_options,json=databaseOptions,proto3" json:"database_options,omitempty"`
+	// How to create the instance and database when they don't exist.
+	CreateDatabase *CreateDatabase `protobuf:"bytes,14,opt,name=create_database,json=createDatabase,proto3" json:"create_database,omitempty"`
+	// How to back up the database before migrations.
+	Backup *Backup `protobuf:"bytes,15,opt,name=backup,proto3" json:"backup,omitempty"`
 }
 
 func (x *Config) Reset() {
@@ -113,6 +190,69 @@ func (x *Config) GetTemplates() map[string]*Template {
 	return nil
 }
 
+func (x *Config) GetSchema() string {
+	if x != nil {
+		return x.Schema
+	}
+	return ""
+}
+
+func (x *Config) GetAllowIdGaps() bool {
+	if x != nil {
+		return x.AllowIdGaps
+	}
+	return false
+}
+
+func (x *Config) GetIdScheme() IdScheme {
+	if x != nil {
+		return x.IdScheme
+	}
+	return IdScheme_SEQUENTIAL
+}
+
+func (x *Config) GetSeeds() string {
+	if x != nil {
+		return x.Seeds
+	}
+	return ""
+}
+
+func (x *Config) GetEnvironments() []string {
+	if x != nil {
+		return x.Environments
+	}
+	return nil
+}
+
+func (x *Config) GetTargets() map[string]*Target {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+func (x *Config) GetDatabaseOptions() *DatabaseOptions {
+	if x != nil {
+		return x.DatabaseOptions
+	}
+	return nil
+}
+
+func (x *Config) GetCreateDatabase() *CreateDatabase {
+	if x != nil {
+		return x.CreateDatabase
+	}
+	return nil
+}
+
+func (x *Config) GetBackup() *Backup {
+	if x != nil {
+		return x.Backup
+	}
+	return nil
+}
+
 var File_jimmy_v1_config_proto protoreflect.FileDescriptor
 
 var file_jimmy_v1_config_proto_rawDesc = []byte{
@@ -120,8 +260,11 @@ var file_jimmy_v1_config_proto_rawDesc = []byte{
 	0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08, 0x6a, 0x69, 0x6d, 0x6d, 0x79, 0x2e, 0x76,
 	0x31, 0x1a, 0x1b, 0x62, 0x75, 0x66, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2f,
 	0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x17,
+	0x6a, 0x69, 0x6d, 0x6d, 0x79, 0x2f, 0x76, 0x31, 0x2f, 0x64, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x15, 0x6a, 0x69, 0x6d, 0x6d, 0x79, 0x2f, 0x76,
+	0x31, 0x2f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x17,
 	0x6a, 0x69, 0x6d, 0x6d, 0x79, 0x2f, 0x76, 0x31, 0x2f, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74,
-	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xda, 0x02, 0x0a, 0x06, 0x43, 0x6f, 0x6e, 0x66,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xdd, 0x06, 0x0a, 0x06, 0x43, 0x6f, 0x6e, 0x66,
 	0x69, 0x67, 0x12, 0x1a, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
 	0x42, 0x06, 0xba, 0x48, 0x03, 0xc8, 0x01, 0x01, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x1d,
 	0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
@@ -138,21 +281,56 @@ var file_jimmy_v1_config_proto_rawDesc = []byte{
 	0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x6a, 0x69, 0x6d, 0x6d, 0x79, 0x2e, 0x76,
 	0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74,
 	0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x09, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74,
-	0x65, 0x73, 0x1a, 0x50, 0x0a, 0x0e, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x45,
-	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x28, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6a, 0x69, 0x6d, 0x6d, 0x79, 0x2e, 0x76, 0x31,
-	0x2e, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
-	0x3a, 0x02, 0x38, 0x01, 0x42, 0x91, 0x01, 0x0a, 0x0c, 0x63, 0x6f, 0x6d, 0x2e, 0x6a, 0x69, 0x6d,
-	0x6d, 0x79, 0x2e, 0x76, 0x31, 0x42, 0x0b, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x50, 0x72, 0x6f,
-	0x74, 0x6f, 0x50, 0x01, 0x5a, 0x33, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
-	0x2f, 0x73, 0x69, 0x6c, 0x61, 0x73, 0x2f, 0x6a, 0x69, 0x6d, 0x6d, 0x79, 0x2f, 0x69, 0x6e, 0x74,
-	0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x70, 0x62, 0x2f, 0x6a, 0x69, 0x6d, 0x6d, 0x79, 0x2f, 0x76,
-	0x31, 0x3b, 0x6a, 0x69, 0x6d, 0x6d, 0x79, 0x76, 0x31, 0xa2, 0x02, 0x03, 0x4a, 0x58, 0x58, 0xaa,
-	0x02, 0x08, 0x4a, 0x69, 0x6d, 0x6d, 0x79, 0x2e, 0x56, 0x31, 0xca, 0x02, 0x08, 0x4a, 0x69, 0x6d,
-	0x6d, 0x79, 0x5c, 0x56, 0x31, 0xe2, 0x02, 0x14, 0x4a, 0x69, 0x6d, 0x6d, 0x79, 0x5c, 0x56, 0x31,
-	0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x09, 0x4a,
-	0x69, 0x6d, 0x6d, 0x79, 0x3a, 0x3a, 0x56, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x12, 0x22, 0x0a, 0x0d, 0x61, 0x6c,
+	0x6c, 0x6f, 0x77, 0x5f, 0x69, 0x64, 0x5f, 0x67, 0x61, 0x70, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0b, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x49, 0x64, 0x47, 0x61, 0x70, 0x73, 0x12, 0x2f,
+	0x0a, 0x09, 0x69, 0x64, 0x5f, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x12, 0x2e, 0x6a, 0x69, 0x6d, 0x6d, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x53,
+	0x63, 0x68, 0x65, 0x6d, 0x65, 0x52, 0x08, 0x69, 0x64, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x65, 0x12,
+	0x14, 0x0a, 0x05, 0x73, 0x65, 0x65, 0x64, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x73, 0x65, 0x65, 0x64, 0x73, 0x12, 0x44, 0x0a, 0x0c, 0x65, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e,
+	0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x09, 0x42, 0x20, 0xba, 0x48, 0x1d,
+	0x92, 0x01, 0x1a, 0x18, 0x01, 0x22, 0x16, 0x72, 0x14, 0x32, 0x12, 0x5e, 0x5b, 0x61, 0x2d, 0x7a,
+	0x5d, 0x5b, 0x61, 0x2d, 0x7a, 0x30, 0x2d, 0x39, 0x5f, 0x2d, 0x5d, 0x2a, 0x24, 0x52, 0x0c, 0x65,
+	0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x37, 0x0a, 0x07, 0x74,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6a,
+	0x69, 0x6d, 0x6d, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x54,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x74, 0x61, 0x72,
+	0x67, 0x65, 0x74, 0x73, 0x12, 0x44, 0x0a, 0x10, 0x64, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65,
+	0x5f, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19,
+	0x2e, 0x6a, 0x69, 0x6d, 0x6d, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61,
+	0x73, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x0f, 0x64, 0x61, 0x74, 0x61, 0x62,
+	0x61, 0x73, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x41, 0x0a, 0x0f, 0x63, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x18, 0x0e, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6a, 0x69, 0x6d, 0x6d, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x0e, 0x63,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x12, 0x28, 0x0a,
+	0x06, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e,
+	0x6a, 0x69, 0x6d, 0x6d, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x52,
+	0x06, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x1a, 0x50, 0x0a, 0x0e, 0x54, 0x65, 0x6d, 0x70, 0x6c,
+	0x61, 0x74, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x28, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6a, 0x69, 0x6d,
+	0x6d, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x4c, 0x0a, 0x0c, 0x54, 0x61, 0x72,
+	0x67, 0x65, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x26, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x6a, 0x69, 0x6d,
+	0x6d, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x2a, 0x29, 0x0a, 0x08, 0x49, 0x64, 0x53, 0x63, 0x68,
+	0x65, 0x6d, 0x65, 0x12, 0x0e, 0x0a, 0x0a, 0x53, 0x45, 0x51, 0x55, 0x45, 0x4e, 0x54, 0x49, 0x41,
+	0x4c, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09, 0x54, 0x49, 0x4d, 0x45, 0x53, 0x54, 0x41, 0x4d, 0x50,
+	0x10, 0x01, 0x42, 0x91, 0x01, 0x0a, 0x0c, 0x63, 0x6f, 0x6d, 0x2e, 0x6a, 0x69, 0x6d, 0x6d, 0x79,
+	0x2e, 0x76, 0x31, 0x42, 0x0b, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x50, 0x72, 0x6f, 0x74, 0x6f,
+	0x50, 0x01, 0x5a, 0x33, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73,
+	0x69, 0x6c, 0x61, 0x73, 0x2f, 0x6a, 0x69, 0x6d, 0x6d, 0x79, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x6e, 0x61, 0x6c, 0x2f, 0x70, 0x62, 0x2f, 0x6a, 0x69, 0x6d, 0x6d, 0x79, 0x2f, 0x76, 0x31, 0x3b,
+	0x6a, 0x69, 0x6d, 0x6d, 0x79, 0x76, 0x31, 0xa2, 0x02, 0x03, 0x4a, 0x58, 0x58, 0xaa, 0x02, 0x08,
+	0x4a, 0x69, 0x6d, 0x6d, 0x79, 0x2e, 0x56, 0x31, 0xca, 0x02, 0x08, 0x4a, 0x69, 0x6d, 0x6d, 0x79,
+	0x5c, 0x56, 0x31, 0xe2, 0x02, 0x14, 0x4a, 0x69, 0x6d, 0x6d, 0x79, 0x5c, 0x56, 0x31, 0x5c, 0x47,
+	0x50, 0x42, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x09, 0x4a, 0x69, 0x6d,
+	0x6d, 0x79, 0x3a, 0x3a, 0x56, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -167,20 +345,33 @@ func file_jimmy_v1_config_proto_rawDescGZIP() []byte {
 	return file_jimmy_v1_config_proto_rawDescData
 }
 
-var file_jimmy_v1_config_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_jimmy_v1_config_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_jimmy_v1_config_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
 var file_jimmy_v1_config_proto_goTypes = []any{
-	(*Config)(nil),   // 0: jimmy.v1.Config
-	nil,              // 1: jimmy.v1.Config.TemplatesEntry
-	(*Template)(nil), // 2: jimmy.v1.Template
+	(IdScheme)(0),           // 0: jimmy.v1.IdScheme
+	(*Config)(nil),          // 1: jimmy.v1.Config
+	nil,                     // 2: jimmy.v1.Config.TemplatesEntry
+	nil,                     // 3: jimmy.v1.Config.TargetsEntry
+	(*DatabaseOptions)(nil), // 4: jimmy.v1.DatabaseOptions
+	(*CreateDatabase)(nil),  // 5: jimmy.v1.CreateDatabase
+	(*Backup)(nil),          // 6: jimmy.v1.Backup
+	(*Template)(nil),        // 7: jimmy.v1.Template
+	(*Target)(nil),          // 8: jimmy.v1.Target
 }
 var file_jimmy_v1_config_proto_depIdxs = []int32{
-	1, // 0: jimmy.v1.Config.templates:type_name -> jimmy.v1.Config.TemplatesEntry
-	2, // 1: jimmy.v1.Config.TemplatesEntry.value:type_name -> jimmy.v1.Template
-	2, // [2:2] is the sub-list for method output_type
-	2, // [2:2] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	2, // 0: jimmy.v1.Config.templates:type_name -> jimmy.v1.Config.TemplatesEntry
+	0, // 1: jimmy.v1.Config.id_scheme:type_name -> jimmy.v1.IdScheme
+	3, // 2: jimmy.v1.Config.targets:type_name -> jimmy.v1.Config.TargetsEntry
+	4, // 3: jimmy.v1.Config.database_options:type_name -> jimmy.v1.DatabaseOptions
+	5, // 4: jimmy.v1.Config.create_database:type_name -> jimmy.v1.CreateDatabase
+	6, // 5: jimmy.v1.Config.backup:type_name -> jimmy.v1.Backup
+	7, // 6: jimmy.v1.Config.TemplatesEntry.value:type_name -> jimmy.v1.Template
+	8, // 7: jimmy.v1.Config.TargetsEntry.value:type_name -> jimmy.v1.Target
+	8, // [8:8] is the sub-list for method output_type
+	8, // [8:8] is the sub-list for method input_type
+	8, // [8:8] is the sub-list for extension type_name
+	8, // [8:8] is the sub-list for extension extendee
+	0, // [0:8] is the sub-list for field type_name
 }
 
 func init() { file_jimmy_v1_config_proto_init() }
@@ -188,19 +379,22 @@ func file_jimmy_v1_config_proto_init() {
 	if File_jimmy_v1_config_proto != nil {
 		return
 	}
+	file_jimmy_v1_database_proto_init()
+	file_jimmy_v1_target_proto_init()
 	file_jimmy_v1_template_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_jimmy_v1_config_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   2,
+			NumEnums:      1,
+			NumMessages:   3,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_jimmy_v1_config_proto_goTypes,
 		DependencyIndexes: file_jimmy_v1_config_proto_depIdxs,
+		EnumInfos:         file_jimmy_v1_config_proto_enumTypes,
 		MessageInfos:      file_jimmy_v1_config_proto_msgTypes,
 	}.Build()
 	File_jimmy_v1_config_proto = out.File
@@ -0,0 +1,389 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.35.1
+// 	protoc        (unknown)
+// source: jimmy/v1/database.proto
+
+package jimmyv1
+
+import (
+	_ "buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Database options set with ALTER DATABASE SET OPTIONS.
+//
+// Unset options aren't managed.
+type DatabaseOptions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The period for which Spanner retains all versions of data (for
+	// example 7d).
+	VersionRetentionPeriod *string `protobuf:"bytes,1,opt,name=version_retention_period,json=versionRetentionPeriod,proto3,oneof" json:"version_retention_period,omitempty"`
+	// The default leader region.
+	DefaultLeader *string `protobuf:"bytes,2,opt,name=default_leader,json=defaultLeader,proto3,oneof" json:"default_leader,omitempty"`
+	// The query optimizer version.
+	OptimizerVersion *int64 `protobuf:"varint,3,opt,name=optimizer_version,json=optimizerVersion,proto3,oneof" json:"optimizer_version,omitempty"`
+	// The default time zone (for example America/New_York).
+	DefaultTimeZone *string `protobuf:"bytes,4,opt,name=default_time_zone,json=defaultTimeZone,proto3,oneof" json:"default_time_zone,omitempty"`
+}
+
+func (x *DatabaseOptions) Reset() {
+	*x = DatabaseOptions{}
+	mi := &file_jimmy_v1_database_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DatabaseOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DatabaseOptions) ProtoMessage() {}
+
+func (x *DatabaseOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_jimmy_v1_database_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DatabaseOptions.ProtoReflect.Descriptor instead.
+func (*DatabaseOptions) Descriptor() ([]byte, []int) {
+	return file_jimmy_v1_database_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DatabaseOptions) GetVersionRetentionPeriod() string {
+	if x != nil && x.VersionRetentionPeriod != nil {
+		return *x.VersionRetentionPeriod
+	}
+	return ""
+}
+
+func (x *DatabaseOptions) GetDefaultLeader() string {
+	if x != nil && x.DefaultLeader != nil {
+		return *x.DefaultLeader
+	}
+	return ""
+}
+
+func (x *DatabaseOptions) GetOptimizerVersion() int64 {
+	if x != nil && x.OptimizerVersion != nil {
+		return *x.OptimizerVersion
+	}
+	return 0
+}
+
+func (x *DatabaseOptions) GetDefaultTimeZone() string {
+	if x != nil && x.DefaultTimeZone != nil {
+		return *x.DefaultTimeZone
+	}
+	return ""
+}
+
+// How to create the instance and database when they don't exist.
+type CreateDatabase struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Whether to create the instance and database.
+	//
+	// Always enabled for the emulator.
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// The instance configuration (for example regional-us-central1).
+	//
+	// Required to create an instance outside of the emulator.
+	InstanceConfig string `protobuf:"bytes,2,opt,name=instance_config,json=instanceConfig,proto3" json:"instance_config,omitempty"`
+	// The compute capacity of the instance.
+	//
+	// Defaults to 100.
+	ProcessingUnits int32 `protobuf:"varint,3,opt,name=processing_units,json=processingUnits,proto3" json:"processing_units,omitempty"`
+	// The database dialect. Only GOOGLE_STANDARD_SQL (default) is supported,
+	// as jimmy's queries are written in GoogleSQL; POSTGRESQL is rejected.
+	Dialect string `protobuf:"bytes,4,opt,name=dialect,proto3" json:"dialect,omitempty"`
+	// Statements to run when creating the database.
+	ExtraStatements []string `protobuf:"bytes,5,rep,name=extra_statements,json=extraStatements,proto3" json:"extra_statements,omitempty"`
+}
+
+func (x *CreateDatabase) Reset() {
+	*x = CreateDatabase{}
+	mi := &file_jimmy_v1_database_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateDatabase) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDatabase) ProtoMessage() {}
+
+func (x *CreateDatabase) ProtoReflect() protoreflect.Message {
+	mi := &file_jimmy_v1_database_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDatabase.ProtoReflect.Descriptor instead.
+func (*CreateDatabase) Descriptor() ([]byte, []int) {
+	return file_jimmy_v1_database_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateDatabase) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *CreateDatabase) GetInstanceConfig() string {
+	if x != nil {
+		return x.InstanceConfig
+	}
+	return ""
+}
+
+func (x *CreateDatabase) GetProcessingUnits() int32 {
+	if x != nil {
+		return x.ProcessingUnits
+	}
+	return 0
+}
+
+func (x *CreateDatabase) GetDialect() string {
+	if x != nil {
+		return x.Dialect
+	}
+	return ""
+}
+
+func (x *CreateDatabase) GetExtraStatements() []string {
+	if x != nil {
+		return x.ExtraStatements
+	}
+	return nil
+}
+
+// How to back up the database before migrations.
+type Backup struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Whether to only record a restore timestamp within the version
+	// retention period instead of creating a backup.
+	//
+	// Always enabled for the emulator, which doesn't support backups.
+	TimestampOnly bool `protobuf:"varint,1,opt,name=timestamp_only,json=timestampOnly,proto3" json:"timestamp_only,omitempty"`
+	// How long backups are kept.
+	//
+	// Defaults to 7 days.
+	Retention *durationpb.Duration `protobuf:"bytes,2,opt,name=retention,proto3" json:"retention,omitempty"`
+	// How long a recorded restore timestamp must stay usable, which must fit
+	// within the database's version_retention_period when timestamp_only is
+	// set.
+	//
+	// Defaults to 1 hour.
+	RestoreWindow *durationpb.Duration `protobuf:"bytes,3,opt,name=restore_window,json=restoreWindow,proto3" json:"restore_window,omitempty"`
+}
+
+func (x *Backup) Reset() {
+	*x = Backup{}
+	mi := &file_jimmy_v1_database_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Backup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Backup) ProtoMessage() {}
+
+func (x *Backup) ProtoReflect() protoreflect.Message {
+	mi := &file_jimmy_v1_database_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Backup.ProtoReflect.Descriptor instead.
+func (*Backup) Descriptor() ([]byte, []int) {
+	return file_jimmy_v1_database_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Backup) GetTimestampOnly() bool {
+	if x != nil {
+		return x.TimestampOnly
+	}
+	return false
+}
+
+func (x *Backup) GetRetention() *durationpb.Duration {
+	if x != nil {
+		return x.Retention
+	}
+	return nil
+}
+
+func (x *Backup) GetRestoreWindow() *durationpb.Duration {
+	if x != nil {
+		return x.RestoreWindow
+	}
+	return nil
+}
+
+var File_jimmy_v1_database_proto protoreflect.FileDescriptor
+
+var file_jimmy_v1_database_proto_rawDesc = []byte{
+	0x0a, 0x17, 0x6a, 0x69, 0x6d, 0x6d, 0x79, 0x2f, 0x76, 0x31, 0x2f, 0x64, 0x61, 0x74, 0x61, 0x62,
+	0x61, 0x73, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08, 0x6a, 0x69, 0x6d, 0x6d, 0x79,
+	0x2e, 0x76, 0x31, 0x1a, 0x1b, 0x62, 0x75, 0x66, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74,
+	0x65, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x22, 0xdb, 0x02, 0x0a, 0x0f, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x4f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x12, 0x54, 0x0a, 0x18, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x5f,
+	0x72, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x15, 0xba, 0x48, 0x12, 0x72, 0x10, 0x32, 0x0e, 0x5e,
+	0x5b, 0x30, 0x2d, 0x39, 0x5d, 0x2b, 0x5b, 0x73, 0x6d, 0x68, 0x64, 0x5d, 0x24, 0x48, 0x00, 0x52,
+	0x16, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f,
+	0x6e, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x88, 0x01, 0x01, 0x12, 0x2a, 0x0a, 0x0e, 0x64, 0x65,
+	0x66, 0x61, 0x75, 0x6c, 0x74, 0x5f, 0x6c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x01, 0x52, 0x0d, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x4c, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x39, 0x0a, 0x11, 0x6f, 0x70, 0x74, 0x69, 0x6d, 0x69,
+	0x7a, 0x65, 0x72, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x03, 0x42, 0x07, 0xba, 0x48, 0x04, 0x22, 0x02, 0x28, 0x01, 0x48, 0x02, 0x52, 0x10, 0x6f, 0x70,
+	0x74, 0x69, 0x6d, 0x69, 0x7a, 0x65, 0x72, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x88, 0x01,
+	0x01, 0x12, 0x2f, 0x0a, 0x11, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x5f, 0x7a, 0x6f, 0x6e, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x0f,
+	0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x5a, 0x6f, 0x6e, 0x65, 0x88,
+	0x01, 0x01, 0x42, 0x1b, 0x0a, 0x19, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x72,
+	0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x42,
+	0x11, 0x0a, 0x0f, 0x5f, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x5f, 0x6c, 0x65, 0x61, 0x64,
+	0x65, 0x72, 0x42, 0x14, 0x0a, 0x12, 0x5f, 0x6f, 0x70, 0x74, 0x69, 0x6d, 0x69, 0x7a, 0x65, 0x72,
+	0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x42, 0x14, 0x0a, 0x12, 0x5f, 0x64, 0x65, 0x66,
+	0x61, 0x75, 0x6c, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x7a, 0x6f, 0x6e, 0x65, 0x22, 0xf6,
+	0x01, 0x0a, 0x0e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73,
+	0x65, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x12, 0x27, 0x0a, 0x0f, 0x69,
+	0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x12, 0x32, 0x0a, 0x10, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x69,
+	0x6e, 0x67, 0x5f, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x42, 0x07,
+	0xba, 0x48, 0x04, 0x1a, 0x02, 0x28, 0x00, 0x52, 0x0f, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73,
+	0x69, 0x6e, 0x67, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x12, 0x42, 0x0a, 0x07, 0x64, 0x69, 0x61, 0x6c,
+	0x65, 0x63, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x42, 0x28, 0xba, 0x48, 0x25, 0x72, 0x23,
+	0x52, 0x00, 0x52, 0x13, 0x47, 0x4f, 0x4f, 0x47, 0x4c, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x4e, 0x44,
+	0x41, 0x52, 0x44, 0x5f, 0x53, 0x51, 0x4c, 0x52, 0x0a, 0x50, 0x4f, 0x53, 0x54, 0x47, 0x52, 0x45,
+	0x53, 0x51, 0x4c, 0x52, 0x07, 0x64, 0x69, 0x61, 0x6c, 0x65, 0x63, 0x74, 0x12, 0x29, 0x0a, 0x10,
+	0x65, 0x78, 0x74, 0x72, 0x61, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73,
+	0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0f, 0x65, 0x78, 0x74, 0x72, 0x61, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x22, 0xaa, 0x01, 0x0a, 0x06, 0x42, 0x61, 0x63, 0x6b,
+	0x75, 0x70, 0x12, 0x25, 0x0a, 0x0e, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x5f,
+	0x6f, 0x6e, 0x6c, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x4f, 0x6e, 0x6c, 0x79, 0x12, 0x37, 0x0a, 0x09, 0x72, 0x65, 0x74,
+	0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x72, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x40, 0x0a, 0x0e, 0x72, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x5f, 0x77, 0x69,
+	0x6e, 0x64, 0x6f, 0x77, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0d, 0x72, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x57, 0x69,
+	0x6e, 0x64, 0x6f, 0x77, 0x42, 0x93, 0x01, 0x0a, 0x0c, 0x63, 0x6f, 0x6d, 0x2e, 0x6a, 0x69, 0x6d,
+	0x6d, 0x79, 0x2e, 0x76, 0x31, 0x42, 0x0d, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x50,
+	0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x33, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x73, 0x69, 0x6c, 0x61, 0x73, 0x2f, 0x6a, 0x69, 0x6d, 0x6d, 0x79, 0x2f, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x70, 0x62, 0x2f, 0x6a, 0x69, 0x6d, 0x6d, 0x79,
+	0x2f, 0x76, 0x31, 0x3b, 0x6a, 0x69, 0x6d, 0x6d, 0x79, 0x76, 0x31, 0xa2, 0x02, 0x03, 0x4a, 0x58,
+	0x58, 0xaa, 0x02, 0x08, 0x4a, 0x69, 0x6d, 0x6d, 0x79, 0x2e, 0x56, 0x31, 0xca, 0x02, 0x08, 0x4a,
+	0x69, 0x6d, 0x6d, 0x79, 0x5c, 0x56, 0x31, 0xe2, 0x02, 0x14, 0x4a, 0x69, 0x6d, 0x6d, 0x79, 0x5c,
+	0x56, 0x31, 0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02,
+	0x09, 0x4a, 0x69, 0x6d, 0x6d, 0x79, 0x3a, 0x3a, 0x56, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_jimmy_v1_database_proto_rawDescOnce sync.Once
+	file_jimmy_v1_database_proto_rawDescData = file_jimmy_v1_database_proto_rawDesc
+)
+
+func file_jimmy_v1_database_proto_rawDescGZIP() []byte {
+	file_jimmy_v1_database_proto_rawDescOnce.Do(func() {
+		file_jimmy_v1_database_proto_rawDescData = protoimpl.X.CompressGZIP(file_jimmy_v1_database_proto_rawDescData)
+	})
+	return file_jimmy_v1_database_proto_rawDescData
+}
+
+var file_jimmy_v1_database_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_jimmy_v1_database_proto_goTypes = []any{
+	(*DatabaseOptions)(nil),     // 0: jimmy.v1.DatabaseOptions
+	(*CreateDatabase)(nil),      // 1: jimmy.v1.CreateDatabase
+	(*Backup)(nil),              // 2: jimmy.v1.Backup
+	(*durationpb.Duration)(nil), // 3: google.protobuf.Duration
+}
+var file_jimmy_v1_database_proto_depIdxs = []int32{
+	3, // 0: jimmy.v1.Backup.retention:type_name -> google.protobuf.Duration
+	3, // 1: jimmy.v1.Backup.restore_window:type_name -> google.protobuf.Duration
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_jimmy_v1_database_proto_init() }
+func file_jimmy_v1_database_proto_init() {
+	if File_jimmy_v1_database_proto != nil {
+		return
+	}
+	file_jimmy_v1_database_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_jimmy_v1_database_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_jimmy_v1_database_proto_goTypes,
+		DependencyIndexes: file_jimmy_v1_database_proto_depIdxs,
+		MessageInfos:      file_jimmy_v1_database_proto_msgTypes,
+	}.Build()
+	File_jimmy_v1_database_proto = out.File
+	file_jimmy_v1_database_proto_rawDesc = nil
+	file_jimmy_v1_database_proto_goTypes = nil
+	file_jimmy_v1_database_proto_depIdxs = nil
+}
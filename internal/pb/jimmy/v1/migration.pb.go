@@ -11,6 +11,7 @@ import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -85,6 +86,18 @@ const (
 	Type_DML Type = 2
 	// The SQL is a partitioned DML statement.
 	Type_PARTITIONED_DML Type = 3
+	// The SQL is a read-only query that must return a single true value or
+	// zero rows, otherwise the migration is aborted.
+	Type_ASSERT Type = 4
+	// The SQL is a DML statement that is run repeatedly, each time in its
+	// own transaction, until no rows are affected.
+	//
+	// The statement should limit the rows it changes using the @batch_size
+	// parameter.
+	Type_BATCHED_DML Type = 5
+	// The statement loads rows from a CSV or newline-delimited JSON file
+	// into a table using mutations.
+	Type_LOAD Type = 6
 )
 
 // Enum value maps for Type.
@@ -94,12 +107,18 @@ var (
 		1: "DDL",
 		2: "DML",
 		3: "PARTITIONED_DML",
+		4: "ASSERT",
+		5: "BATCHED_DML",
+		6: "LOAD",
 	}
 	Type_value = map[string]int32{
 		"AUTOMATIC":       0,
 		"DDL":             1,
 		"DML":             2,
 		"PARTITIONED_DML": 3,
+		"ASSERT":          4,
+		"BATCHED_DML":     5,
+		"LOAD":            6,
 	}
 )
 
@@ -130,6 +149,132 @@ func (Type) EnumDescriptor() ([]byte, []int) {
 	return file_jimmy_v1_migration_proto_rawDescGZIP(), []int{1}
 }
 
+// Options for a batched DML statement.
+type BatchedOptions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The value of the @batch_size parameter.
+	//
+	// Defaults to 1000.
+	Size int64 `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	// The time to wait between iterations.
+	Pause *durationpb.Duration `protobuf:"bytes,2,opt,name=pause,proto3" json:"pause,omitempty"`
+	// The maximum number of iterations, after which the statement fails.
+	//
+	// Defaults to unlimited.
+	MaxIterations int64 `protobuf:"varint,3,opt,name=max_iterations,json=maxIterations,proto3" json:"max_iterations,omitempty"`
+}
+
+func (x *BatchedOptions) Reset() {
+	*x = BatchedOptions{}
+	mi := &file_jimmy_v1_migration_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchedOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchedOptions) ProtoMessage() {}
+
+func (x *BatchedOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_jimmy_v1_migration_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchedOptions.ProtoReflect.Descriptor instead.
+func (*BatchedOptions) Descriptor() ([]byte, []int) {
+	return file_jimmy_v1_migration_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *BatchedOptions) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *BatchedOptions) GetPause() *durationpb.Duration {
+	if x != nil {
+		return x.Pause
+	}
+	return nil
+}
+
+func (x *BatchedOptions) GetMaxIterations() int64 {
+	if x != nil {
+		return x.MaxIterations
+	}
+	return 0
+}
+
+// Options for a load statement.
+type LoadOptions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The CSV (.csv) or newline-delimited JSON (.ndjson, .jsonl) file to
+	// load, relative to the migration file.
+	File string `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	// The table to write the rows to.
+	Table string `protobuf:"bytes,2,opt,name=table,proto3" json:"table,omitempty"`
+}
+
+func (x *LoadOptions) Reset() {
+	*x = LoadOptions{}
+	mi := &file_jimmy_v1_migration_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoadOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadOptions) ProtoMessage() {}
+
+func (x *LoadOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_jimmy_v1_migration_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadOptions.ProtoReflect.Descriptor instead.
+func (*LoadOptions) Descriptor() ([]byte, []int) {
+	return file_jimmy_v1_migration_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *LoadOptions) GetFile() string {
+	if x != nil {
+		return x.File
+	}
+	return ""
+}
+
+func (x *LoadOptions) GetTable() string {
+	if x != nil {
+		return x.Table
+	}
+	return ""
+}
+
 // A SQL statement.
 type Statement struct {
 	state         protoimpl.MessageState
@@ -137,6 +282,8 @@ type Statement struct {
 	unknownFields protoimpl.UnknownFields
 
 	// A SQL statement to execute.
+	//
+	// Not used by load statements.
 	Sql string `protobuf:"bytes,1,opt,name=sql,proto3" json:"sql,omitempty"`
 	// The environment in which to run the statement.
 	Env Environment `protobuf:"varint,2,opt,name=env,proto3,enum=jimmy.v1.Environment" json:"env,omitempty"`
@@ -144,11 +291,26 @@ type Statement struct {
 	Type Type `protobuf:"varint,3,opt,name=type,proto3,enum=jimmy.v1.Type" json:"type,omitempty"`
 	// The file descriptor set for the statement.
 	FileDescriptorSet *string `protobuf:"bytes,4,opt,name=file_descriptor_set,json=fileDescriptorSet,proto3,oneof" json:"file_descriptor_set,omitempty"`
+	// The user-defined environments in which to run the statement.
+	//
+	// If empty the statement runs in all environments.
+	Envs []string `protobuf:"bytes,5,rep,name=envs,proto3" json:"envs,omitempty"`
+	// The user-defined environments in which to skip the statement.
+	ExcludeEnvs []string `protobuf:"bytes,6,rep,name=exclude_envs,json=excludeEnvs,proto3" json:"exclude_envs,omitempty"`
+	// A read-only SQL query that must return true for the statement to run.
+	//
+	// The query is evaluated against the live schema just before the
+	// statement runs, for example to check information_schema.
+	When *string `protobuf:"bytes,7,opt,name=when,proto3,oneof" json:"when,omitempty"`
+	// The options for a BATCHED_DML statement.
+	Batched *BatchedOptions `protobuf:"bytes,8,opt,name=batched,proto3" json:"batched,omitempty"`
+	// The options for a LOAD statement.
+	Load *LoadOptions `protobuf:"bytes,9,opt,name=load,proto3" json:"load,omitempty"`
 }
 
 func (x *Statement) Reset() {
 	*x = Statement{}
-	mi := &file_jimmy_v1_migration_proto_msgTypes[0]
+	mi := &file_jimmy_v1_migration_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -160,7 +322,7 @@ func (x *Statement) String() string {
 func (*Statement) ProtoMessage() {}
 
 func (x *Statement) ProtoReflect() protoreflect.Message {
-	mi := &file_jimmy_v1_migration_proto_msgTypes[0]
+	mi := &file_jimmy_v1_migration_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -173,7 +335,7 @@ func (x *Statement) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Statement.ProtoReflect.Descriptor instead.
 func (*Statement) Descriptor() ([]byte, []int) {
-	return file_jimmy_v1_migration_proto_rawDescGZIP(), []int{0}
+	return file_jimmy_v1_migration_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *Statement) GetSql() string {
@@ -204,6 +366,41 @@ func (x *Statement) GetFileDescriptorSet() string {
 	return ""
 }
 
+func (x *Statement) GetEnvs() []string {
+	if x != nil {
+		return x.Envs
+	}
+	return nil
+}
+
+func (x *Statement) GetExcludeEnvs() []string {
+	if x != nil {
+		return x.ExcludeEnvs
+	}
+	return nil
+}
+
+func (x *Statement) GetWhen() string {
+	if x != nil && x.When != nil {
+		return *x.When
+	}
+	return ""
+}
+
+func (x *Statement) GetBatched() *BatchedOptions {
+	if x != nil {
+		return x.Batched
+	}
+	return nil
+}
+
+func (x *Statement) GetLoad() *LoadOptions {
+	if x != nil {
+		return x.Load
+	}
+	return nil
+}
+
 // A Jimmy migration file.
 type Migration struct {
 	state         protoimpl.MessageState
@@ -220,14 +417,16 @@ type Migration struct {
 	//
 	// If the specified migration has already been run then this
 	// migration will be skipped.
-	SquashId *int32 `protobuf:"varint,2,opt,name=squash_id,json=squashId,proto3,oneof" json:"squash_id,omitempty"`
+	SquashId *int64 `protobuf:"varint,2,opt,name=squash_id,json=squashId,proto3,oneof" json:"squash_id,omitempty"`
 	// The Protocol Buffers file descriptor sets for the migration.
 	FileDescriptorSets map[string]*descriptorpb.FileDescriptorSet `protobuf:"bytes,6,rep,name=file_descriptor_sets,json=fileDescriptorSets,proto3" json:"file_descriptor_sets,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Whether to back up the database before running the migration.
+	BackupBefore bool `protobuf:"varint,7,opt,name=backup_before,json=backupBefore,proto3" json:"backup_before,omitempty"`
 }
 
 func (x *Migration) Reset() {
 	*x = Migration{}
-	mi := &file_jimmy_v1_migration_proto_msgTypes[1]
+	mi := &file_jimmy_v1_migration_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -239,7 +438,7 @@ func (x *Migration) String() string {
 func (*Migration) ProtoMessage() {}
 
 func (x *Migration) ProtoReflect() protoreflect.Message {
-	mi := &file_jimmy_v1_migration_proto_msgTypes[1]
+	mi := &file_jimmy_v1_migration_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -252,7 +451,7 @@ func (x *Migration) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Migration.ProtoReflect.Descriptor instead.
 func (*Migration) Descriptor() ([]byte, []int) {
-	return file_jimmy_v1_migration_proto_rawDescGZIP(), []int{1}
+	return file_jimmy_v1_migration_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *Migration) GetUpgrade() []*Statement {
@@ -262,7 +461,7 @@ func (x *Migration) GetUpgrade() []*Statement {
 	return nil
 }
 
-func (x *Migration) GetSquashId() int32 {
+func (x *Migration) GetSquashId() int64 {
 	if x != nil && x.SquashId != nil {
 		return *x.SquashId
 	}
@@ -276,6 +475,13 @@ func (x *Migration) GetFileDescriptorSets() map[string]*descriptorpb.FileDescrip
 	return nil
 }
 
+func (x *Migration) GetBackupBefore() bool {
+	if x != nil {
+		return x.BackupBefore
+	}
+	return false
+}
+
 var File_jimmy_v1_migration_proto protoreflect.FileDescriptor
 
 var file_jimmy_v1_migration_proto_rawDesc = []byte{
@@ -285,56 +491,92 @@ var file_jimmy_v1_migration_proto_rawDesc = []byte{
 	0x74, 0x65, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
 	0x6f, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
 	0x75, 0x66, 0x2f, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x22, 0xbf, 0x01, 0x0a, 0x09, 0x53, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e,
-	0x74, 0x12, 0x18, 0x0a, 0x03, 0x73, 0x71, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x06,
-	0xba, 0x48, 0x03, 0xc8, 0x01, 0x01, 0x52, 0x03, 0x73, 0x71, 0x6c, 0x12, 0x27, 0x0a, 0x03, 0x65,
-	0x6e, 0x76, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x6a, 0x69, 0x6d, 0x6d, 0x79,
-	0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x52,
-	0x03, 0x65, 0x6e, 0x76, 0x12, 0x22, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x6a, 0x69, 0x6d, 0x6d, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x79,
-	0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x33, 0x0a, 0x13, 0x66, 0x69, 0x6c, 0x65,
-	0x5f, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x5f, 0x73, 0x65, 0x74, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x11, 0x66, 0x69, 0x6c, 0x65, 0x44, 0x65, 0x73,
-	0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x53, 0x65, 0x74, 0x88, 0x01, 0x01, 0x42, 0x16, 0x0a,
-	0x14, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f,
-	0x72, 0x5f, 0x73, 0x65, 0x74, 0x22, 0xb4, 0x02, 0x0a, 0x09, 0x4d, 0x69, 0x67, 0x72, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x12, 0x2d, 0x0a, 0x07, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x18, 0x01,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6a, 0x69, 0x6d, 0x6d, 0x79, 0x2e, 0x76, 0x31, 0x2e,
-	0x53, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x07, 0x75, 0x70, 0x67, 0x72, 0x61,
-	0x64, 0x65, 0x12, 0x20, 0x0a, 0x09, 0x73, 0x71, 0x75, 0x61, 0x73, 0x68, 0x5f, 0x69, 0x64, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x08, 0x73, 0x71, 0x75, 0x61, 0x73, 0x68, 0x49,
-	0x64, 0x88, 0x01, 0x01, 0x12, 0x5d, 0x0a, 0x14, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x64, 0x65, 0x73,
-	0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x5f, 0x73, 0x65, 0x74, 0x73, 0x18, 0x06, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x6a, 0x69, 0x6d, 0x6d, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x69,
-	0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x44, 0x65, 0x73, 0x63,
-	0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x53, 0x65, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
-	0x12, 0x66, 0x69, 0x6c, 0x65, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x53,
-	0x65, 0x74, 0x73, 0x1a, 0x69, 0x0a, 0x17, 0x46, 0x69, 0x6c, 0x65, 0x44, 0x65, 0x73, 0x63, 0x72,
-	0x69, 0x70, 0x74, 0x6f, 0x72, 0x53, 0x65, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10,
-	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
-	0x12, 0x38, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x22, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
-	0x66, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72,
-	0x53, 0x65, 0x74, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x0c,
-	0x0a, 0x0a, 0x5f, 0x73, 0x71, 0x75, 0x61, 0x73, 0x68, 0x5f, 0x69, 0x64, 0x2a, 0x36, 0x0a, 0x0b,
-	0x45, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x07, 0x0a, 0x03, 0x41,
-	0x4c, 0x4c, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x47, 0x4f, 0x4f, 0x47, 0x4c, 0x45, 0x5f, 0x43,
-	0x4c, 0x4f, 0x55, 0x44, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x45, 0x4d, 0x55, 0x4c, 0x41, 0x54,
-	0x4f, 0x52, 0x10, 0x02, 0x2a, 0x3c, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0d, 0x0a, 0x09,
-	0x41, 0x55, 0x54, 0x4f, 0x4d, 0x41, 0x54, 0x49, 0x43, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03, 0x44,
-	0x44, 0x4c, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x44, 0x4d, 0x4c, 0x10, 0x02, 0x12, 0x13, 0x0a,
-	0x0f, 0x50, 0x41, 0x52, 0x54, 0x49, 0x54, 0x49, 0x4f, 0x4e, 0x45, 0x44, 0x5f, 0x44, 0x4d, 0x4c,
-	0x10, 0x03, 0x42, 0x94, 0x01, 0x0a, 0x0c, 0x63, 0x6f, 0x6d, 0x2e, 0x6a, 0x69, 0x6d, 0x6d, 0x79,
-	0x2e, 0x76, 0x31, 0x42, 0x0e, 0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x72,
-	0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x33, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
-	0x6d, 0x2f, 0x73, 0x69, 0x6c, 0x61, 0x73, 0x2f, 0x6a, 0x69, 0x6d, 0x6d, 0x79, 0x2f, 0x69, 0x6e,
-	0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x70, 0x62, 0x2f, 0x6a, 0x69, 0x6d, 0x6d, 0x79, 0x2f,
-	0x76, 0x31, 0x3b, 0x6a, 0x69, 0x6d, 0x6d, 0x79, 0x76, 0x31, 0xa2, 0x02, 0x03, 0x4a, 0x58, 0x58,
-	0xaa, 0x02, 0x08, 0x4a, 0x69, 0x6d, 0x6d, 0x79, 0x2e, 0x56, 0x31, 0xca, 0x02, 0x08, 0x4a, 0x69,
-	0x6d, 0x6d, 0x79, 0x5c, 0x56, 0x31, 0xe2, 0x02, 0x14, 0x4a, 0x69, 0x6d, 0x6d, 0x79, 0x5c, 0x56,
-	0x31, 0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x09,
-	0x4a, 0x69, 0x6d, 0x6d, 0x79, 0x3a, 0x3a, 0x56, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x33,
+	0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x22, 0x8e, 0x01, 0x0a, 0x0e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x4f,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1b, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x42, 0x07, 0xba, 0x48, 0x04, 0x22, 0x02, 0x28, 0x00, 0x52, 0x04, 0x73,
+	0x69, 0x7a, 0x65, 0x12, 0x2f, 0x0a, 0x05, 0x70, 0x61, 0x75, 0x73, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x05, 0x70,
+	0x61, 0x75, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x0e, 0x6d, 0x61, 0x78, 0x5f, 0x69, 0x74, 0x65, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x42, 0x07, 0xba, 0x48,
+	0x04, 0x22, 0x02, 0x28, 0x00, 0x52, 0x0d, 0x6d, 0x61, 0x78, 0x49, 0x74, 0x65, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x22, 0x47, 0x0a, 0x0b, 0x4c, 0x6f, 0x61, 0x64, 0x4f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x12, 0x1a, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x42, 0x06, 0xba, 0x48, 0x03, 0xc8, 0x01, 0x01, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x12,
+	0x1c, 0x0a, 0x05, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x06,
+	0xba, 0x48, 0x03, 0xc8, 0x01, 0x01, 0x52, 0x05, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x22, 0xc1, 0x03,
+	0x0a, 0x09, 0x53, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x73,
+	0x71, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x73, 0x71, 0x6c, 0x12, 0x27, 0x0a,
+	0x03, 0x65, 0x6e, 0x76, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x6a, 0x69, 0x6d,
+	0x6d, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65, 0x6e,
+	0x74, 0x52, 0x03, 0x65, 0x6e, 0x76, 0x12, 0x22, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x6a, 0x69, 0x6d, 0x6d, 0x79, 0x2e, 0x76, 0x31, 0x2e,
+	0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x33, 0x0a, 0x13, 0x66, 0x69,
+	0x6c, 0x65, 0x5f, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x5f, 0x73, 0x65,
+	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x11, 0x66, 0x69, 0x6c, 0x65, 0x44,
+	0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x53, 0x65, 0x74, 0x88, 0x01, 0x01, 0x12,
+	0x12, 0x0a, 0x04, 0x65, 0x6e, 0x76, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x65,
+	0x6e, 0x76, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x65,
+	0x6e, 0x76, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x65, 0x78, 0x63, 0x6c, 0x75,
+	0x64, 0x65, 0x45, 0x6e, 0x76, 0x73, 0x12, 0x20, 0x0a, 0x04, 0x77, 0x68, 0x65, 0x6e, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xba, 0x48, 0x04, 0x72, 0x02, 0x10, 0x01, 0x48, 0x01, 0x52,
+	0x04, 0x77, 0x68, 0x65, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x32, 0x0a, 0x07, 0x62, 0x61, 0x74, 0x63,
+	0x68, 0x65, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6a, 0x69, 0x6d, 0x6d,
+	0x79, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x4f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x52, 0x07, 0x62, 0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x12, 0x29, 0x0a, 0x04,
+	0x6c, 0x6f, 0x61, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6a, 0x69, 0x6d,
+	0x6d, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x52, 0x04, 0x6c, 0x6f, 0x61, 0x64, 0x3a, 0x47, 0xba, 0x48, 0x44, 0x1a, 0x42, 0x0a, 0x0d,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x73, 0x71, 0x6c, 0x12, 0x0f, 0x73,
+	0x71, 0x6c, 0x20, 0x69, 0x73, 0x20, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x1a, 0x20,
+	0x68, 0x61, 0x73, 0x28, 0x74, 0x68, 0x69, 0x73, 0x2e, 0x6c, 0x6f, 0x61, 0x64, 0x29, 0x20, 0x7c,
+	0x7c, 0x20, 0x74, 0x68, 0x69, 0x73, 0x2e, 0x73, 0x71, 0x6c, 0x20, 0x21, 0x3d, 0x20, 0x27, 0x27,
+	0x42, 0x16, 0x0a, 0x14, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x6f, 0x72, 0x5f, 0x73, 0x65, 0x74, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x77, 0x68, 0x65,
+	0x6e, 0x22, 0xd9, 0x02, 0x0a, 0x09, 0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x2d, 0x0a, 0x07, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x13, 0x2e, 0x6a, 0x69, 0x6d, 0x6d, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x07, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x12, 0x20,
+	0x0a, 0x09, 0x73, 0x71, 0x75, 0x61, 0x73, 0x68, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x03, 0x48, 0x00, 0x52, 0x08, 0x73, 0x71, 0x75, 0x61, 0x73, 0x68, 0x49, 0x64, 0x88, 0x01, 0x01,
+	0x12, 0x5d, 0x0a, 0x14, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70,
+	0x74, 0x6f, 0x72, 0x5f, 0x73, 0x65, 0x74, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2b,
+	0x2e, 0x6a, 0x69, 0x6d, 0x6d, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x69, 0x67, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
+	0x6f, 0x72, 0x53, 0x65, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x12, 0x66, 0x69, 0x6c,
+	0x65, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x53, 0x65, 0x74, 0x73, 0x12,
+	0x23, 0x0a, 0x0d, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x5f, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x42, 0x65,
+	0x66, 0x6f, 0x72, 0x65, 0x1a, 0x69, 0x0a, 0x17, 0x46, 0x69, 0x6c, 0x65, 0x44, 0x65, 0x73, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x53, 0x65, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x38, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x22, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f,
+	0x72, 0x53, 0x65, 0x74, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42,
+	0x0c, 0x0a, 0x0a, 0x5f, 0x73, 0x71, 0x75, 0x61, 0x73, 0x68, 0x5f, 0x69, 0x64, 0x2a, 0x36, 0x0a,
+	0x0b, 0x45, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x07, 0x0a, 0x03,
+	0x41, 0x4c, 0x4c, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x47, 0x4f, 0x4f, 0x47, 0x4c, 0x45, 0x5f,
+	0x43, 0x4c, 0x4f, 0x55, 0x44, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x45, 0x4d, 0x55, 0x4c, 0x41,
+	0x54, 0x4f, 0x52, 0x10, 0x02, 0x2a, 0x63, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0d, 0x0a,
+	0x09, 0x41, 0x55, 0x54, 0x4f, 0x4d, 0x41, 0x54, 0x49, 0x43, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03,
+	0x44, 0x44, 0x4c, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x44, 0x4d, 0x4c, 0x10, 0x02, 0x12, 0x13,
+	0x0a, 0x0f, 0x50, 0x41, 0x52, 0x54, 0x49, 0x54, 0x49, 0x4f, 0x4e, 0x45, 0x44, 0x5f, 0x44, 0x4d,
+	0x4c, 0x10, 0x03, 0x12, 0x0a, 0x0a, 0x06, 0x41, 0x53, 0x53, 0x45, 0x52, 0x54, 0x10, 0x04, 0x12,
+	0x0f, 0x0a, 0x0b, 0x42, 0x41, 0x54, 0x43, 0x48, 0x45, 0x44, 0x5f, 0x44, 0x4d, 0x4c, 0x10, 0x05,
+	0x12, 0x08, 0x0a, 0x04, 0x4c, 0x4f, 0x41, 0x44, 0x10, 0x06, 0x42, 0x94, 0x01, 0x0a, 0x0c, 0x63,
+	0x6f, 0x6d, 0x2e, 0x6a, 0x69, 0x6d, 0x6d, 0x79, 0x2e, 0x76, 0x31, 0x42, 0x0e, 0x4d, 0x69, 0x67,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x33, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73, 0x69, 0x6c, 0x61, 0x73, 0x2f,
+	0x6a, 0x69, 0x6d, 0x6d, 0x79, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x70,
+	0x62, 0x2f, 0x6a, 0x69, 0x6d, 0x6d, 0x79, 0x2f, 0x76, 0x31, 0x3b, 0x6a, 0x69, 0x6d, 0x6d, 0x79,
+	0x76, 0x31, 0xa2, 0x02, 0x03, 0x4a, 0x58, 0x58, 0xaa, 0x02, 0x08, 0x4a, 0x69, 0x6d, 0x6d, 0x79,
+	0x2e, 0x56, 0x31, 0xca, 0x02, 0x08, 0x4a, 0x69, 0x6d, 0x6d, 0x79, 0x5c, 0x56, 0x31, 0xe2, 0x02,
+	0x14, 0x4a, 0x69, 0x6d, 0x6d, 0x79, 0x5c, 0x56, 0x31, 0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74,
+	0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x09, 0x4a, 0x69, 0x6d, 0x6d, 0x79, 0x3a, 0x3a, 0x56,
+	0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -350,26 +592,32 @@ func file_jimmy_v1_migration_proto_rawDescGZIP() []byte {
 }
 
 var file_jimmy_v1_migration_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_jimmy_v1_migration_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_jimmy_v1_migration_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_jimmy_v1_migration_proto_goTypes = []any{
 	(Environment)(0),                       // 0: jimmy.v1.Environment
 	(Type)(0),                              // 1: jimmy.v1.Type
-	(*Statement)(nil),                      // 2: jimmy.v1.Statement
-	(*Migration)(nil),                      // 3: jimmy.v1.Migration
-	nil,                                    // 4: jimmy.v1.Migration.FileDescriptorSetsEntry
-	(*descriptorpb.FileDescriptorSet)(nil), // 5: google.protobuf.FileDescriptorSet
+	(*BatchedOptions)(nil),                 // 2: jimmy.v1.BatchedOptions
+	(*LoadOptions)(nil),                    // 3: jimmy.v1.LoadOptions
+	(*Statement)(nil),                      // 4: jimmy.v1.Statement
+	(*Migration)(nil),                      // 5: jimmy.v1.Migration
+	nil,                                    // 6: jimmy.v1.Migration.FileDescriptorSetsEntry
+	(*durationpb.Duration)(nil),            // 7: google.protobuf.Duration
+	(*descriptorpb.FileDescriptorSet)(nil), // 8: google.protobuf.FileDescriptorSet
 }
 var file_jimmy_v1_migration_proto_depIdxs = []int32{
-	0, // 0: jimmy.v1.Statement.env:type_name -> jimmy.v1.Environment
-	1, // 1: jimmy.v1.Statement.type:type_name -> jimmy.v1.Type
-	2, // 2: jimmy.v1.Migration.upgrade:type_name -> jimmy.v1.Statement
-	4, // 3: jimmy.v1.Migration.file_descriptor_sets:type_name -> jimmy.v1.Migration.FileDescriptorSetsEntry
-	5, // 4: jimmy.v1.Migration.FileDescriptorSetsEntry.value:type_name -> google.protobuf.FileDescriptorSet
-	5, // [5:5] is the sub-list for method output_type
-	5, // [5:5] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	7, // 0: jimmy.v1.BatchedOptions.pause:type_name -> google.protobuf.Duration
+	0, // 1: jimmy.v1.Statement.env:type_name -> jimmy.v1.Environment
+	1, // 2: jimmy.v1.Statement.type:type_name -> jimmy.v1.Type
+	2, // 3: jimmy.v1.Statement.batched:type_name -> jimmy.v1.BatchedOptions
+	3, // 4: jimmy.v1.Statement.load:type_name -> jimmy.v1.LoadOptions
+	4, // 5: jimmy.v1.Migration.upgrade:type_name -> jimmy.v1.Statement
+	6, // 6: jimmy.v1.Migration.file_descriptor_sets:type_name -> jimmy.v1.Migration.FileDescriptorSetsEntry
+	8, // 7: jimmy.v1.Migration.FileDescriptorSetsEntry.value:type_name -> google.protobuf.FileDescriptorSet
+	8, // [8:8] is the sub-list for method output_type
+	8, // [8:8] is the sub-list for method input_type
+	8, // [8:8] is the sub-list for extension type_name
+	8, // [8:8] is the sub-list for extension extendee
+	0, // [0:8] is the sub-list for field type_name
 }
 
 func init() { file_jimmy_v1_migration_proto_init() }
@@ -377,15 +625,15 @@ func file_jimmy_v1_migration_proto_init() {
 	if File_jimmy_v1_migration_proto != nil {
 		return
 	}
-	file_jimmy_v1_migration_proto_msgTypes[0].OneofWrappers = []any{}
-	file_jimmy_v1_migration_proto_msgTypes[1].OneofWrappers = []any{}
+	file_jimmy_v1_migration_proto_msgTypes[2].OneofWrappers = []any{}
+	file_jimmy_v1_migration_proto_msgTypes[3].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_jimmy_v1_migration_proto_rawDesc,
 			NumEnums:      2,
-			NumMessages:   3,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   0,
 		},